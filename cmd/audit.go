@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/pkg/auth"
+	"github.com/cli/go-gh/pkg/term"
+	"github.com/spf13/cobra"
+)
+
+// formatCSV is an output format of audit findings.
+const formatCSV = "csv"
+
+func NewCmdAudit() *cobra.Command {
+	var auditCmd = &cobra.Command{
+		Use:          "audit",
+		Short:        "Report webhooks that send data to unapproved hosts or are configured insecurely.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("format")
+			if format != formatText && format != formatJSON && format != formatCSV {
+				return fmt.Errorf("unknown format %q, use text, json or csv\n", format)
+			}
+			allowedHostsFile, _ := cmd.Flags().GetString("allowed-hosts")
+			file, err := os.Open(allowedHostsFile)
+			if err != nil {
+				return fmt.Errorf("could not open allowed hosts: %w\n", err)
+			}
+			defer file.Close()
+			allowedHosts, err := readAllowedHosts(file)
+			if err != nil {
+				return err
+			}
+			sensitive, _ := cmd.Flags().GetStringSlice("sensitive-events")
+			sensitive, err = expandEvents(sensitive, eventNames(catalog.Events))
+			if err != nil {
+				return err
+			}
+			rules := auditRules(allowedHosts, sensitive)
+			now := time.Now()
+
+			repos, err := getRepos(cmd)
+			if err != nil {
+				return err
+			}
+			var findings []finding
+			for _, repo := range repos {
+				name := repo.Owner() + "/" + repo.Name()
+				hooks, err := getWebhooks(repo)
+				if isNotFound(err) {
+					fmt.Fprintf(os.Stderr, "Skipping %s, its webhooks could not be read\n", name)
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("could not get webhooks of %s: %w\n", name, err)
+				}
+				findings = append(findings, runRules(rules, name, hooks, now)...)
+			}
+			if owner, _ := cmd.Flags().GetString("owner"); owner != "" {
+				host, _ := auth.DefaultHost()
+				if len(repos) > 0 {
+					host = repos[0].Host()
+				}
+				// Users have no webhooks of their own, and only organization
+				// owners can read them, so other errors are not fatal.
+				hooks, err := getWebhooks(orgTarget{host: host, org: owner})
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Skipping the webhooks of the %s organization: %v\n", owner, err)
+				}
+				findings = append(findings, runRules(rules, owner, hooks, now)...)
+			}
+			sortFindings(findings)
+
+			switch format {
+			case formatJSON:
+				return writeFindingsJSON(os.Stdout, findings)
+			case formatCSV:
+				return writeFindingsCSV(os.Stdout, findings)
+			default:
+				return printFindings(term.FromEnv(), findings)
+			}
+		},
+	}
+	auditCmd.Flags().String("allowed-hosts", "", "A file listing the hosts webhooks may deliver to, one per line. \"*.example.com\" allows every subdomain of example.com.")
+	auditCmd.Flags().String("owner", "", "Audit the webhooks of this organization and of every one of its repositories, or of every repository of a user.")
+	auditCmd.Flags().String("format", formatText, "Output format: text, json or csv.")
+	auditCmd.Flags().StringSlice("sensitive-events", []string{groupPrefix + "security"}, "Events that expose sensitive data. Accepts the same patterns and groups as create --events.")
+	_ = auditCmd.MarkFlagRequired("allowed-hosts")
+	return auditCmd
+}
+
+// auditRules are the checks run by audit.
+func auditRules(allowedHosts, sensitiveEvents []string) []rule {
+	return []rule{
+		allowedHostsRule(allowedHosts),
+		insecureSSLRule,
+		missingSecretRule,
+		{
+			ID:          "sensitive-events",
+			Description: "Security events are sent, which expose vulnerabilities.",
+			Severity:    severityWarning,
+			Check: func(ctx ruleContext, hook Hook) string {
+				if contains(hook.Events, allEvents) {
+					return "receives every event, including security events"
+				}
+				shared := sharedEvents(hook.Events, sensitiveEvents)
+				if len(shared) == 0 {
+					return ""
+				}
+				return fmt.Sprintf("receives security events %s", strings.Join(shared, ", "))
+			},
+		},
+	}
+}
+
+// allowedHostsRule flags hooks delivering to hosts that are not allowed.
+func allowedHostsRule(allowedHosts []string) rule {
+	return rule{
+		ID:          "disallowed-host",
+		Description: "Payloads are sent to a host that is not allowed.",
+		Severity:    severityError,
+		Check: func(ctx ruleContext, hook Hook) string {
+			host := hookHost(hook)
+			if hostAllowed(host, allowedHosts) {
+				return ""
+			}
+			return fmt.Sprintf("%s is not an allowed host", host)
+		},
+	}
+}
+
+// readAllowedHosts reads one host per line, ignoring blank lines and comments
+// starting with #.
+func readAllowedHosts(r io.Reader) ([]string, error) {
+	var hosts []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if host := strings.ToLower(strings.TrimSpace(line)); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read allowed hosts: %w\n", err)
+	}
+	return hosts, nil
+}
+
+// hostAllowed reports whether host is one of allowed, where "*.example.com"
+// matches any subdomain of example.com.
+func hostAllowed(host string, allowed []string) bool {
+	for _, a := range allowed {
+		if a == host {
+			return true
+		}
+		if strings.HasPrefix(a, "*.") && strings.HasSuffix(host, strings.TrimPrefix(a, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeFindingsCSV(w io.Writer, findings []finding) error {
+	out := csv.NewWriter(w)
+	_ = out.Write([]string{"severity", "rule", "repo", "hook_id", "url", "message"})
+	for _, f := range findings {
+		_ = out.Write([]string{string(f.Severity), f.Rule, f.Repo, strconv.Itoa(f.HookId), f.Url, f.Message})
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_readAllowedHosts(t *testing.T) {
+	hosts, err := readAllowedHosts(strings.NewReader(`
+# CI
+ci.example.com
+*.Relay.example.org  # chat relays
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ci.example.com", "*.relay.example.org"}, hosts)
+}
+
+func Test_hostAllowed(t *testing.T) {
+	allowed := []string{"ci.example.com", "*.relay.example.org"}
+	assert.True(t, hostAllowed("ci.example.com", allowed))
+	assert.True(t, hostAllowed("eu.relay.example.org", allowed))
+	assert.False(t, hostAllowed("relay.example.org", allowed))
+	assert.False(t, hostAllowed("evil-ci.example.com", allowed))
+	assert.False(t, hostAllowed("example.net", allowed))
+}
+
+func Test_auditRules(t *testing.T) {
+	hooks := []Hook{
+		{Id: 1, Events: []string{"push"}, Config: HookConfig{Url: "https://ci.example.com", InsecureSSL: "0", Secret: "********"}},
+		{Id: 2, Events: []string{"dependabot_alert", "push"}, Config: HookConfig{Url: "https://example.net", InsecureSSL: "1"}},
+		{Id: 3, Events: []string{"*"}, Config: HookConfig{Url: "https://ci.example.com", InsecureSSL: "0", Secret: "********"}},
+	}
+	rules := auditRules([]string{"ci.example.com"}, []string{"code_scanning_alert", "dependabot_alert"})
+	findings := runRules(rules, "my-org", hooks, time.Now())
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Rule+" "+f.Message)
+	}
+	assert.Equal(t, []string{
+		"disallowed-host example.net is not an allowed host",
+		"insecure-ssl SSL certificate verification is disabled",
+		"missing-secret no secret is configured, so payloads cannot be verified",
+		"sensitive-events receives security events dependabot_alert",
+		"sensitive-events receives every event, including security events",
+	}, got)
+}
+
+func Test_writeFindingsCSV(t *testing.T) {
+	var out bytes.Buffer
+	err := writeFindingsCSV(&out, []finding{
+		{Rule: "disallowed-host", Severity: severityError, Repo: "my-org/app", HookId: 2, Url: "https://example.net", Message: "example.net is not an allowed host"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `severity,rule,repo,hook_id,url,message
+error,disallowed-host,my-org/app,2,https://example.net,example.net is not an allowed host
+`, out.String())
+}
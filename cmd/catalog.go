@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+//go:generate go run ./data/gen
+
+//go:embed data/events.json
+var catalogData []byte
+
+// eventCatalog is the list of webhook events shipped with the extension, so
+// that it works offline. It is generated from a release of octokit/webhooks,
+// whose version it records, with the availability of each event taken from
+// data/availability.json.
+type eventCatalog struct {
+	Version string  `json:"version"`
+	Events  []Event `json:"events"`
+}
+
+// The scopes a webhook can be created for. Each event in the catalog lists the
+// scopes it is available for.
+const (
+	scopeRepository   = "repository"
+	scopeOrganization = "organization"
+	scopeApp          = "app"
+	scopeEnterprise   = "enterprise"
+)
+
+var hookScopes = []string{scopeRepository, scopeOrganization, scopeApp, scopeEnterprise}
+
+type Event struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Actions     []string `json:"actions,omitempty"`
+	// Availability lists the scopes the event can be subscribed to in.
+	Availability []string `json:"availability,omitempty"`
+	// GHES is the first GitHub Enterprise Server version that sends the event,
+	// empty when every supported version does, or "none" when only GitHub.com
+	// does.
+	GHES     string            `json:"ghes,omitempty"`
+	Examples []json.RawMessage `json:"examples,omitempty"`
+}
+
+func (e Event) availableFor(scope string) bool {
+	return contains(e.Availability, scope)
+}
+
+var catalog = loadCatalog()
+
+func loadCatalog() eventCatalog {
+	var c eventCatalog
+	if err := json.Unmarshal(catalogData, &c); err != nil {
+		panic("invalid embedded event catalog: " + err.Error())
+	}
+	return c
+}
+
+// catalogEvent looks up an event in the embedded catalog by name.
+func catalogEvent(name string) (Event, bool) {
+	for _, e := range catalog.Events {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return Event{}, false
+}
+
+// catalogEvents returns the catalog events that can be used in webhooks of the
+// given scope. For example, some events are only available for organizations
+// or GitHub Apps.
+// See: https://docs.github.com/developers/webhooks-and-events/webhooks/webhook-events-and-payloads
+func catalogEvents(scope string) []Event {
+	return eventsForScope(catalog.Events, scope)
+}
+
+// eventsForScope filters events down to the ones available in scope. Events
+// without a known availability, such as new events in a downloaded list, are
+// left out, as GitHub may reject them.
+func eventsForScope(events []Event, scope string) []Event {
+	var filtered []Event
+	for _, e := range events {
+		if e.availableFor(scope) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+func validScope(scope string) error {
+	if !contains(hookScopes, scope) {
+		return fmt.Errorf("unknown scope %q, available scopes: %s", scope, strings.Join(hookScopes, ", "))
+	}
+	return nil
+}
+
+func eventNames(events []Event) []string {
+	var names []string
+	for _, e := range events {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+func eventDescriptions(events []Event) map[string]string {
+	descriptions := map[string]string{}
+	for _, e := range events {
+		descriptions[e.Name] = e.Description
+	}
+	return descriptions
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_catalog(t *testing.T) {
+	assert.NotEmpty(t, catalog.Version)
+	seen := map[string]bool{}
+	for _, e := range catalog.Events {
+		assert.Falsef(t, seen[e.Name], "duplicate event %s", e.Name)
+		seen[e.Name] = true
+		assert.NotEmptyf(t, e.Description, "event %s has no description", e.Name)
+		assert.NotEmptyf(t, e.Availability, "event %s has no availability", e.Name)
+		if e.GHES != "" && e.GHES != notOnGHES {
+			_, ok := parseVersion(e.GHES)
+			assert.Truef(t, ok, "event %s has an invalid GHES version %q", e.Name, e.GHES)
+		}
+	}
+}
+
+func Test_eventsForScope(t *testing.T) {
+	events := []Event{
+		{Name: "push", Availability: []string{scopeRepository, scopeOrganization, scopeApp}},
+		{Name: "organization", Availability: []string{scopeOrganization, scopeApp, scopeEnterprise}},
+		{Name: "installation", Availability: []string{scopeApp}},
+		{Name: "brand_new_event"},
+	}
+	tests := []struct {
+		name  string
+		scope string
+		want  []string
+	}{
+		{
+			name:  "repository",
+			scope: scopeRepository,
+			want:  []string{"push"},
+		},
+		{
+			name:  "organization",
+			scope: scopeOrganization,
+			want:  []string{"push", "organization"},
+		},
+		{
+			name:  "app",
+			scope: scopeApp,
+			want:  []string{"push", "organization", "installation"},
+		},
+		{
+			name:  "enterprise",
+			scope: scopeEnterprise,
+			want:  []string{"organization"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, eventNames(eventsForScope(events, tt.scope)))
+		})
+	}
+}
+
+func Test_catalogEvents(t *testing.T) {
+	names := eventNames(catalogEvents(scopeRepository))
+	assert.Contains(t, names, "push")
+	assert.Contains(t, names, "workflow_run")
+	assert.NotContains(t, names, "installation")
+	assert.NotContains(t, names, "organization")
+	assert.NotContains(t, names, "user")
+
+	names = eventNames(catalogEvents(scopeEnterprise))
+	assert.Contains(t, names, "user")
+	assert.Contains(t, names, "enterprise")
+	assert.NotContains(t, names, "push")
+}
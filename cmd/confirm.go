@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/cli/go-gh/pkg/tableprinter"
+	"github.com/cli/go-gh/pkg/term"
+	"github.com/lucasmelin/gh-hook/tui"
+)
+
+// redactedSecret replaces secrets in output.
+const redactedSecret = "********"
+
+// confirmHooks lists the hooks that an action such as "delete" applies to and
+// asks whether to go ahead. With dryRun the hooks are only listed, and with yes
+// the question is skipped, which is required when not running in a terminal.
+func confirmHooks(t term.Term, action string, hooks []Hook, dryRun, yes bool) (bool, error) {
+	if dryRun {
+		fmt.Fprintf(t.Out(), "Would %s %d webhooks:\n", action, len(hooks))
+		return false, printHookSummary(t, hooks)
+	}
+	if yes {
+		return true, nil
+	}
+	if !t.IsTerminalOutput() {
+		return false, fmt.Errorf("pass --yes to %s webhooks without confirmation", action)
+	}
+	fmt.Fprintf(t.Out(), "About to %s %d webhooks:\n", action, len(hooks))
+	if err := printHookSummary(t, hooks); err != nil {
+		return false, err
+	}
+	confirmed, err := tui.Confirm("Continue?")
+	if err != nil {
+		return false, err
+	}
+	if !confirmed {
+		fmt.Fprintln(t.Out(), "Nothing was changed")
+	}
+	return confirmed, nil
+}
+
+// printHookSummary prints the ID, URL and events of each hook.
+func printHookSummary(t term.Term, hooks []Hook) error {
+	width, _, err := t.Size()
+	if err != nil {
+		width = 80
+	}
+	tp := tableprinter.New(t.Out(), t.IsTerminalOutput(), width)
+	for _, hook := range hooks {
+		tp.AddField(strconv.Itoa(hook.Id))
+		tp.AddField(hook.Config.Url)
+		tp.AddField(strings.Join(hook.Events, ", "))
+		tp.EndRow()
+	}
+	return tp.Render()
+}
+
+// printHookJSON prints a hook as indented JSON with its secret redacted, to
+// show what a dry run would have sent.
+func printHookJSON(w io.Writer, hook Hook) error {
+	if hook.Config.Secret != "" {
+		hook.Config.Secret = redactedSecret
+	}
+	data, err := json.MarshalIndent(hook, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not convert hook to JSON: %w\n", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_printHookJSON(t *testing.T) {
+	hook := Hook{
+		Name:   "web",
+		Active: true,
+		Events: []string{"push"},
+		Config: HookConfig{Url: "https://example.com", Secret: "hunter2"},
+	}
+	var out bytes.Buffer
+	assert.NoError(t, printHookJSON(&out, hook))
+	assert.NotContains(t, out.String(), "hunter2")
+	assert.Contains(t, out.String(), `"secret": "********"`)
+	assert.Equal(t, "hunter2", hook.Config.Secret)
+}
@@ -5,12 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
-	"github.com/cli/go-gh"
-	"github.com/cli/go-gh/pkg/api"
-	"github.com/cli/go-gh/pkg/repository"
 	"github.com/lucasmelin/gh-hook/tui"
 	"github.com/spf13/cobra"
 )
@@ -21,153 +21,412 @@ func NewCmdCreate() *cobra.Command {
 		Short:        "Create a new repository webhook",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			repo, err := getRepo(cmd)
+			target, err := getTarget(cmd)
 			if err != nil {
 				return err
 			}
+			if err := checkTarget(target, "create"); err != nil {
+				return err
+			}
 
-			fmt.Printf("Creating new webhook for %s\n", repo.Name())
+			fmt.Printf("Creating new webhook for %s\n", targetName(target))
 
 			refreshEvents, _ := cmd.Flags().GetBool("refresh-events")
-			events, err := getEvents(refreshEvents)
+			events, err := getEvents(refreshEvents, targetKind(target), target.Host())
 			if err != nil {
 				return fmt.Errorf("could not get events: %w\n", err)
 			}
+			names := eventNames(events)
 
 			fileInput, _ := cmd.Flags().GetString("file")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			force, _ := cmd.Flags().GetBool("force")
+			policyFile, _ := cmd.Flags().GetString("policy")
+			generate, _ := cmd.Flags().GetBool("generate-secret")
+			secretOutput, _ := cmd.Flags().GetString("secret-output")
+			var source secretSource
+			source.env, _ = cmd.Flags().GetString("secret-env")
+			source.file, _ = cmd.Flags().GetString("secret-file")
+			source.helper, _ = cmd.Flags().GetString("secret-helper")
+
+			templateName, _ := cmd.Flags().GetString("template")
+			hookUrl, _ := cmd.Flags().GetString("url")
+			eventPatterns, _ := cmd.Flags().GetStringSlice("events")
+			flagEvents, err := expandEvents(eventPatterns, names)
+			if err != nil {
+				return err
+			}
+			var tmpl Hook
+			if templateName != "" {
+				tmpl, err = getTemplate(templateName)
+				if err != nil {
+					return err
+				}
+			}
 
-			var newHook Hook
-			if len(fileInput) > 0 {
+			defaults := applyTemplate(Hook{
+				Events: flagEvents,
+				Config: HookConfig{Url: hookUrl},
+			}, tmpl)
+
+			var newHooks []Hook
+			switch {
+			case len(fileInput) > 0:
 				file, err := os.Open(fileInput)
 				if err != nil {
 					return fmt.Errorf("could not open JSON file: %w\n", err)
 				}
-				newHook, err = hookFromInput(file)
-			} else {
-				newHook, err = hookFromPrompt(events)
+				newHooks, err = hooksFromInput(file, filepath.Dir(fileInput))
+				if err != nil {
+					return err
+				}
+				for i := range newHooks {
+					newHooks[i].Events, err = expandEvents(newHooks[i].Events, names)
+					if err != nil {
+						return err
+					}
+					newHooks[i] = applyTemplate(newHooks[i], defaults)
+				}
+			case hookUrl != "" && len(defaults.Events) > 0:
+				newHook := defaults
+				newHook.Name = "web"
+				newHook.Active = true
+				newHooks = []Hook{newHook}
+			default:
+				if templateName == "" {
+					tmpl, err = chooseTemplate()
+					if err != nil {
+						return err
+					}
+					defaults = applyTemplate(defaults, tmpl)
+				}
+				newHook, err := hookFromPrompt(events, !generate && !source.isSet(), defaults)
 				if err != nil {
 					return err
 				}
+				newHooks = []Hook{newHook}
 			}
 
-			if err := createHook(repo, newHook); err != nil {
+			for i := range newHooks {
+				if generate {
+					newHooks[i].Config.Secret, err = generateSecret()
+					if err != nil {
+						return err
+					}
+				} else if source.isSet() {
+					newHooks[i].Config.Secret, err = source.resolve(newHooks[i])
+					if err != nil {
+						return fmt.Errorf("could not get webhook secret: %w\n", err)
+					}
+				}
+			}
+			// Every hook is checked before any is created, so that a
+			// violation does not leave a file half applied.
+			if err := enforcePolicy(policyFile, force, newHooks...); err != nil {
 				return err
 			}
+
+			var generated []Hook
+			for _, newHook := range newHooks {
+				if dryRun {
+					fmt.Println("Would create webhook:")
+					if err := printHookJSON(os.Stdout, newHook); err != nil {
+						return err
+					}
+					continue
+				}
+				created, err := createHook(target, newHook)
+				if err != nil {
+					// The hooks created before the failure keep their
+					// generated secret, which is lost unless written out now.
+					if outErr := outputSecrets(secretOutput, generated); outErr != nil {
+						fmt.Fprint(os.Stderr, outErr)
+					}
+					return err
+				}
+				if generate {
+					created.Config.Secret = newHook.Config.Secret
+					generated = append(generated, created)
+				}
+			}
+			if dryRun {
+				return nil
+			}
 			fmt.Println("Successfully created hook 🪝")
-			return nil
+			return outputSecrets(secretOutput, generated)
 		},
 	}
-	createCmd.Flags().Bool("refresh-events", false, "Download the list of events from https://octokit.github.io/webhooks By default, a hardcoded list of known events will be used.")
-	createCmd.Flags().String("file", "", "Provide the webhook data as a JSON file.")
+	createCmd.Flags().Bool("refresh-events", false, "Use the list of events from https://octokit.github.io/webhooks, downloaded at most once a day. By default, the event catalog shipped with the extension will be used.")
+	createCmd.Flags().String("file", "", "Provide the webhook data as a JSON file. The file may contain a single webhook or an array of webhooks.")
+	createCmd.Flags().Bool("dry-run", false, "Show the webhooks that would be created without creating them.")
+	createCmd.Flags().Bool("force", false, "Create the webhooks even if they violate the policy.")
+	createCmd.Flags().String("policy", "", "Check the webhooks against this policy file instead of gh-hook/policy.json in the gh config directory.")
+	createCmd.Flags().Bool("generate-secret", false, "Generate a random secret for each webhook instead of prompting for one.")
+	createCmd.Flags().String("secret-output", "", "Append generated secrets to this file instead of printing them.")
+	createCmd.Flags().String("secret-env", "", "Read the webhook secret from this environment variable.")
+	createCmd.Flags().String("secret-file", "", "Read the webhook secret from this file.")
+	createCmd.Flags().String("secret-helper", "", "Run this command and use its output as the webhook secret. The webhook URL is available to it as GH_HOOK_URL.")
+	createCmd.Flags().String("template", "", "Use a template for the events, content type and SSL settings. Built-in templates: argocd, jenkins, slack. User templates are read from the gh config directory.")
+	createCmd.Flags().String("url", "", "The URL that will receive the webhook payloads.")
+	createCmd.Flags().StringSlice("events", nil, "Events to subscribe to. Accepts event names, \"*\" for all events, glob patterns such as \"pull_request*\" and the groups @ci, @issues, @pr and @security.")
+	createCmd.MarkFlagsMutuallyExclusive("generate-secret", "secret-env", "secret-file", "secret-helper")
+	addTargetFlags(createCmd)
 	return createCmd
 }
 
-func hookFromInput(file io.Reader) (Hook, error) {
-	newHook := Hook{}
+// hookFromInput parses a single webhook. Webhooks are active unless the input
+// says otherwise, as when created through the API. A relative secret_file is
+// read from dir.
+func hookFromInput(file io.Reader, dir string) (Hook, error) {
+	newHook := Hook{Active: true}
 	parser := json.NewDecoder(file)
 	if err := parser.Decode(&newHook); err != nil {
 		return newHook, fmt.Errorf("could not parse JSON data %+v: %w", file, err)
 	}
+	if err := resolveSecret(&newHook.Config, dir); err != nil {
+		return newHook, fmt.Errorf("could not resolve webhook secret: %w", err)
+	}
 	return newHook, nil
 }
 
-func hookFromPrompt(events []string) (Hook, error) {
-	hookUrl, err := tui.Input(false, "Webhook URL: ")
+// createFromPrompt prompts for a template and the settings of a new hook, and
+// creates it.
+func createFromPrompt(target hookTarget) error {
+	events, err := getEvents(false, targetKind(target), target.Host())
 	if err != nil {
-		return Hook{}, fmt.Errorf("could not get webhook URL: %w\n", err)
+		return fmt.Errorf("could not get events: %w\n", err)
 	}
-	hookEvents, err := tui.ChooseMany("Events to receive", events)
+	tmpl, err := chooseTemplate()
 	if err != nil {
-		return Hook{}, fmt.Errorf("could not choose events: %w\n", err)
+		return err
 	}
-	secret, err := tui.Input(true, "Webhook secret (optional): ")
+	newHook, err := hookFromPrompt(events, true, tmpl)
 	if err != nil {
-		return Hook{}, fmt.Errorf("could not get webhook secret: %w\n", err)
+		return err
+	}
+	if err := enforcePolicy("", false, newHook); err != nil {
+		return err
 	}
-	contentType, err := tui.ChooseOne("Content Type", []string{"json", "form"})
+	if _, err := createHook(target, newHook); err != nil {
+		return err
+	}
+	fmt.Println("Successfully created hook 🪝")
+	return nil
+}
+
+// hooksFromInput parses either a single webhook object or an array of
+// webhooks, read from a file in dir.
+func hooksFromInput(file io.Reader, dir string) ([]Hook, error) {
+	data, err := io.ReadAll(file)
 	if err != nil {
-		return Hook{}, fmt.Errorf("could not choose content type: %w\n", err)
+		return nil, fmt.Errorf("could not read JSON data: %w", err)
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var items []json.RawMessage
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, fmt.Errorf("could not parse JSON data: %w", err)
+		}
+		hooks := make([]Hook, len(items))
+		for i, item := range items {
+			hooks[i], err = hookFromInput(bytes.NewReader(item), dir)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return hooks, nil
 	}
-	sslChoice, err := tui.ChooseOne("Insecure SSL", []string{"true", "false"})
+	hook, err := hookFromInput(bytes.NewReader(data), dir)
 	if err != nil {
-		return Hook{}, fmt.Errorf("could not choose insecure SSL option: %w\n", err)
+		return nil, err
 	}
-	ssl := "0"
-	if sslChoice == "true" {
-		ssl = "1"
+	return []Hook{hook}, nil
+}
+
+// hookFromPrompt shows a form for the settings of a new webhook, prefilled with
+// defaults.
+func hookFromPrompt(events []Event, promptSecret bool, defaults Hook) (Hook, error) {
+	defaults.Active = true
+	hook, err := hookFromForm("New webhook", events, defaults, promptSecret, "optional", false)
+	if err != nil {
+		return Hook{}, err
+	}
+	hook.Name = "web"
+	return hook, nil
+}
+
+// hookFromForm shows a single form for every setting of a webhook, starting
+// from current. The secret field is only shown when promptSecret is set, and
+// the events and state are left as they are when configOnly is set.
+func hookFromForm(title string, events []Event, current Hook, promptSecret bool, secretPlaceholder string, configOnly bool) (Hook, error) {
+	names := eventNames(events)
+	choices, descriptions := eventChoices(events)
+	contentType := current.Config.ContentType
+	if contentType == "" {
+		contentType = "json"
+	}
+
+	fields := []tui.FormField{
+		{
+			Key:         "url",
+			Label:       "Webhook URL",
+			Kind:        tui.TextField,
+			Placeholder: "https://example.com/webhook",
+			Value:       current.Config.Url,
+			Validate: func(f tui.FormField) error {
+				return validateHookUrl(f.Value)
+			},
+		},
+	}
+	if !configOnly {
+		fields = append(fields, tui.FormField{
+			Key:          "events",
+			Label:        "Events",
+			Kind:         tui.MultiChoiceField,
+			Values:       current.Events,
+			Options:      choices,
+			Descriptions: descriptions,
+			Validate: func(f tui.FormField) error {
+				if len(f.Values) == 0 {
+					return fmt.Errorf("choose at least one event, or * for every event")
+				}
+				_, err := expandEvents(f.Values, names)
+				return err
+			},
+		}, tui.FormField{
+			Key:         "patterns",
+			Label:       "Extra events",
+			Kind:        tui.TextField,
+			Placeholder: "optional, e.g. pull_request*, @ci",
+			Validate: func(f tui.FormField) error {
+				_, err := expandEvents(strings.Split(f.Value, ","), names)
+				return err
+			},
+		})
+	}
+	if promptSecret {
+		fields = append(fields, tui.FormField{
+			Key:         "secret",
+			Label:       "Webhook secret",
+			Kind:        tui.SecretField,
+			Placeholder: secretPlaceholder,
+		})
+	}
+	fields = append(fields,
+		tui.FormField{
+			Key:     "content_type",
+			Label:   "Content Type",
+			Kind:    tui.ChoiceField,
+			Value:   contentType,
+			Options: []string{"json", "form"},
+		},
+		tui.FormField{
+			Key:     "insecure_ssl",
+			Label:   "Insecure SSL",
+			Kind:    tui.ChoiceField,
+			Value:   strconv.FormatBool(current.Config.InsecureSSL == "1"),
+			Options: []string{"false", "true"},
+		},
+	)
+	if !configOnly {
+		fields = append(fields, tui.FormField{
+			Key:     "active",
+			Label:   "Webhook Active",
+			Kind:    tui.ChoiceField,
+			Value:   strconv.FormatBool(current.Active),
+			Options: []string{"true", "false"},
+		})
 	}
-	activeChoice, err := tui.ChooseOne("Webhook Active", []string{"true", "false"})
+
+	values, err := tui.Form(title, fields)
 	if err != nil {
-		return Hook{}, fmt.Errorf("could not choose webhook active option: %w\n", err)
+		return Hook{}, fmt.Errorf("could not get webhook settings: %w\n", err)
+	}
+	active, hookEvents := current.Active, current.Events
+	if !configOnly {
+		patterns := append(values["events"].Values, strings.Split(values["patterns"].Value, ",")...)
+		hookEvents, err = expandEvents(patterns, names)
+		if err != nil {
+			return Hook{}, err
+		}
+		active = values["active"].Value == "true"
 	}
-	active := false
-	if activeChoice == "true" {
-		active = true
+	ssl := "0"
+	if values["insecure_ssl"].Value == "true" {
+		ssl = "1"
 	}
 
 	return Hook{
-		Name:   "web",
 		Active: active,
 		Events: hookEvents,
 		Config: HookConfig{
-			Url:         hookUrl,
-			ContentType: contentType,
+			Url:         values["url"].Value,
+			ContentType: values["content_type"].Value,
 			InsecureSSL: ssl,
-			Secret:      secret,
+			Secret:      values["secret"].Value,
 		},
 	}, nil
 }
 
-func createHook(repo repository.Repository, data Hook) error {
-	hookOpts := api.ClientOptions{
-		Host: repo.Host(),
+func validateHookUrl(hookUrl string) error {
+	u, err := url.Parse(hookUrl)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("enter an http or https URL")
 	}
-	client, err := gh.RESTClient(&hookOpts)
+	return nil
+}
+
+func createHook(target hookTarget, data Hook) (Hook, error) {
+	if err := checkTarget(target, "create"); err != nil {
+		return Hook{}, err
+	}
+	created, err := postHook(target, data)
+	if err != nil {
+		return Hook{}, err
+	}
+	recordHistory(target, "create", nil, &created)
+	return created, nil
+}
+
+// postHook creates a hook without recording it in the history, for callers
+// that record it differently.
+func postHook(target hookTarget, data Hook) (Hook, error) {
+	client, err := restClient(target)
 	if err != nil {
-		return fmt.Errorf("error creating REST client: %w\n", err)
+		return Hook{}, fmt.Errorf("error creating REST client: %w\n", err)
 	}
 
 	jsonData, err := json.Marshal(data)
 	if err != nil {
-		return fmt.Errorf("could not convert responses to JSON: %w\n", err)
+		return Hook{}, fmt.Errorf("could not convert responses to JSON: %w\n", err)
 	}
 
-	apiUrl := fmt.Sprintf("repos/%s/%s/hooks", repo.Owner(), repo.Name())
-	if err := client.Post(apiUrl, bytes.NewBuffer(jsonData), nil); err != nil {
-		return fmt.Errorf("could not create new webhook: %w\n", err)
+	created := Hook{}
+	apiUrl := hooksPath(target)
+	if err := client.Post(apiUrl, bytes.NewBuffer(jsonData), &created); err != nil {
+		return Hook{}, fmt.Errorf("could not create new webhook: %w\n", err)
 	}
-	return nil
+	return created, nil
 }
 
-func getEvents(refresh bool) ([]string, error) {
-	if !refresh {
-		return knownEvents, nil
+// getEvents returns the events that can be subscribed to in scope on host.
+// When refresh is set, the events are downloaded from the octokit/webhooks
+// index and cached, and the availability of each event is taken from the
+// embedded catalog. On GitHub Enterprise Server, events added after the version
+// it runs are left out.
+func getEvents(refresh bool, scope string, host string) ([]Event, error) {
+	events := catalog.Events
+	if refresh {
+		var err error
+		events, err = refreshEvents(false)
+		if err != nil {
+			return nil, err
+		}
 	}
-	client := &http.Client{}
-	assetURL := "https://octokit.github.io/webhooks/payload-examples/api.github.com/index.json"
-	req, err := http.NewRequest("GET", assetURL, nil)
+	events = eventsForScope(events, scope)
+	version, err := serverVersion(host)
 	if err != nil {
-		return nil, err
-	}
-	req.Header.Add("Accept", "application/json")
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func(Body io.ReadCloser) {
-		_ = Body.Close()
-	}(resp.Body)
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	var events []Event
-	if err := json.Unmarshal(body, &events); err != nil {
-		return nil, err
-	}
-	var eventNames []string
-	for _, e := range events {
-		eventNames = append(eventNames, e.Name)
+		fmt.Fprintf(os.Stderr, "%v, showing every event\n", err)
+		return events, nil
 	}
-	return eventNames, nil
+	return eventsForServer(events, version), nil
 }
@@ -92,7 +92,7 @@ func Test_createHook(t *testing.T) {
 			if tt.httpMocks != nil {
 				tt.httpMocks()
 			}
-			err := createHook(tt.repo, tt.data)
+			_, err := createHook(tt.repo, tt.data)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("createHook(%v, %v) error = %+v, wantErr %+v", tt.repo, tt.data, err, tt.wantErr)
 			}
@@ -106,13 +106,13 @@ func Test_getEvents(t *testing.T) {
 		name      string
 		refresh   bool
 		httpMocks func()
-		want      []string
+		want      []Event
 		wantErr   bool
 	}{
 		{
 			name:    "no refresh, only known events",
 			refresh: false,
-			want:    knownEvents,
+			want:    catalogEvents(scopeRepository),
 		},
 		{
 			name:    "successfully refresh list of events",
@@ -151,17 +151,25 @@ func Test_getEvents(t *testing.T) {
 }
 ]`)
 			},
-			want: []string{"branch_protection_rule"},
+			want: []Event{
+				{
+					Name:         "branch_protection_rule",
+					Description:  "Activity related to a branch protection rule.",
+					Actions:      []string{"created", "deleted", "edited"},
+					Availability: []string{"repository", "organization", "app"},
+				},
+			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			stubConfig(t, testConfig())
+			t.Setenv("XDG_CACHE_HOME", t.TempDir())
 			t.Cleanup(gock.Off)
 			if tt.httpMocks != nil {
 				tt.httpMocks()
 			}
-			got, err := getEvents(tt.refresh)
+			got, err := getEvents(tt.refresh, scopeRepository, "github.com")
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("getEvents() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -175,6 +183,8 @@ func Test_getEvents(t *testing.T) {
 
 func stubConfig(t *testing.T, cfgStr string) {
 	t.Helper()
+	// Keep the history of changes made by tests out of the real state directory.
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
 	old := config.Read
 	config.Read = func() (*config.Config, error) {
 		return config.ReadFromString(cfgStr), nil
@@ -231,7 +241,7 @@ func Test_hookFromInput(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := hookFromInput(tt.data)
+			got, err := hookFromInput(tt.data, "")
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("getEvents() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -239,3 +249,84 @@ func Test_hookFromInput(t *testing.T) {
 		})
 	}
 }
+
+func Test_hooksFromInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    io.Reader
+		want    []Hook
+		wantErr bool
+	}{
+		{
+			name: "single hook",
+			data: strings.NewReader(`{"active": true, "events": ["push"], "config": {"url": "https://example.com"}}`),
+			want: []Hook{
+				{
+					Active: true,
+					Events: []string{"push"},
+					Config: HookConfig{Url: "https://example.com"},
+				},
+			},
+		},
+		{
+			name: "array of hooks",
+			data: strings.NewReader(`
+[
+  {"active": true, "events": ["push"], "config": {"url": "https://example.com"}},
+  {"events": ["release"], "config": {"url": "https://example.org"}},
+  {"active": false, "events": ["star"], "config": {"url": "https://example.net"}}
+]`),
+			want: []Hook{
+				{
+					Active: true,
+					Events: []string{"push"},
+					Config: HookConfig{Url: "https://example.com"},
+				},
+				{
+					Active: true,
+					Events: []string{"release"},
+					Config: HookConfig{Url: "https://example.org"},
+				},
+				{
+					Active: false,
+					Events: []string{"star"},
+					Config: HookConfig{Url: "https://example.net"},
+				},
+			},
+		},
+		{
+			name:    "invalid JSON",
+			data:    strings.NewReader(`[{"active": true`),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hooksFromInput(tt.data, "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("hooksFromInput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equalf(t, tt.want, got, "hooksFromInput(%v)", tt.data)
+		})
+	}
+}
+
+func Test_validateHookUrl(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: "https://example.com/hook"},
+		{url: "http://localhost:8080"},
+		{url: "", wantErr: true},
+		{url: "example.com", wantErr: true},
+		{url: "ftp://example.com", wantErr: true},
+		{url: "https://", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := validateHookUrl(tt.url)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/lucasmelin/gh-hook/tui"
+	"github.com/spf13/cobra"
+)
+
+// recentDeliveries is the number of deliveries shown for the selected hook.
+const recentDeliveries = 20
+
+// runDashboard opens the interactive dashboard. Creating and editing hooks
+// closes the dashboard to show their prompts, and reopens it afterwards.
+func runDashboard(cmd *cobra.Command) error {
+	target, err := getTarget(cmd)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("🪝 Webhooks of %s", targetName(target))
+	for {
+		result, err := tui.Dashboard(title, newDashboardSource(target))
+		if err != nil {
+			return err
+		}
+		switch result.Action {
+		case "create":
+			err = createFromPrompt(target)
+		case "edit":
+			err = editFromPrompt(target, result.ID, editOptions{})
+		default:
+			return nil
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+}
+
+func newDashboardSource(target hookTarget) tui.DashboardSource {
+	var mu sync.Mutex
+	hooks := map[string]Hook{}
+
+	return tui.DashboardSource{
+		Load: func() ([]tui.DashboardEntry, error) {
+			currentHooks, err := getWebhooks(target)
+			if err != nil {
+				return nil, err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			var entries []tui.DashboardEntry
+			for i, choice := range formatHookChoices(currentHooks) {
+				id := strconv.Itoa(currentHooks[i].Id)
+				hooks[id] = currentHooks[i]
+				entries = append(entries, tui.DashboardEntry{ID: id, Title: choice})
+			}
+			return entries, nil
+		},
+		Details: func(id string) (string, []tui.DashboardEntry, error) {
+			mu.Lock()
+			hook := hooks[id]
+			mu.Unlock()
+			deliveries, err := getDeliveries(target, id, recentDeliveries)
+			if err != nil {
+				return formatHookDetails(hook), nil, nil
+			}
+			var entries []tui.DashboardEntry
+			for _, d := range deliveries {
+				entries = append(entries, tui.DashboardEntry{ID: strconv.Itoa(d.Id), Title: formatDelivery(d)})
+			}
+			return formatHookDetails(hook), entries, nil
+		},
+		DetailsTitle: "Recent deliveries",
+		Actions: []tui.DashboardAction{
+			{
+				Name: "create",
+				Key:  key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "create")),
+				Exit: true,
+			},
+			{
+				Name: "edit",
+				Key:  key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit")),
+				Exit: true,
+			},
+			{
+				Name: "toggle",
+				Key:  key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "toggle active")),
+				Run: func(id, _ string) (string, error) {
+					mu.Lock()
+					active := !hooks[id].Active
+					mu.Unlock()
+					if err := setHookActive(target, id, active); err != nil {
+						return "", err
+					}
+					if active {
+						return fmt.Sprintf("Activated hook %s", id), nil
+					}
+					return fmt.Sprintf("Deactivated hook %s", id), nil
+				},
+			},
+			{
+				Name: "ping",
+				Key:  key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "ping")),
+				Run: func(id, _ string) (string, error) {
+					if err := pingHook(target, id); err != nil {
+						return "", err
+					}
+					return fmt.Sprintf("Pinged hook %s", id), nil
+				},
+			},
+			{
+				Name:          "redeliver",
+				Key:           key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "redeliver")),
+				NeedsSubEntry: true,
+				Run: func(id, deliveryId string) (string, error) {
+					if err := redeliver(target, id, deliveryId); err != nil {
+						return "", err
+					}
+					return fmt.Sprintf("Redelivered %s", deliveryId), nil
+				},
+			},
+			{
+				Name:    "delete",
+				Key:     key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete")),
+				Confirm: true,
+				Run: func(id, _ string) (string, error) {
+					if err := deleteHook(target, id); err != nil {
+						return "", err
+					}
+					return fmt.Sprintf("Deleted hook %s", id), nil
+				},
+			},
+		},
+	}
+}
+
+func formatHookDetails(hook Hook) string {
+	yesNo := func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return "no"
+	}
+	lastResponse := lastResponseStatus(hook)
+	if hook.LastResponse != nil && hook.LastResponse.Message != "" {
+		lastResponse += " (" + hook.LastResponse.Message + ")"
+	}
+	lines := [][2]string{
+		{"ID", strconv.Itoa(hook.Id)},
+		{"URL", hook.Config.Url},
+		{"Active", yesNo(hook.Active)},
+		{"Events", strings.Join(hook.Events, ", ")},
+		{"Content type", hook.Config.ContentType},
+		{"Insecure SSL", yesNo(hook.Config.InsecureSSL == "1")},
+		{"Last response", lastResponse},
+		{"Updated", hook.UpdatedAt},
+	}
+	var s strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			s.WriteRune('\n')
+		}
+		fmt.Fprintf(&s, "%-14s %s", line[0]+":", line[1])
+	}
+	return s.String()
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_formatHookDetails(t *testing.T) {
+	hook := Hook{
+		Id:     12345678,
+		Active: true,
+		Events: []string{"push", "pull_request"},
+		Config: HookConfig{
+			Url:         "https://example.com/webhook",
+			ContentType: "json",
+			InsecureSSL: "0",
+		},
+		UpdatedAt:    "2019-06-03T00:57:16Z",
+		LastResponse: &HookResponse{Code: 502, Status: "failed", Message: "Bad Gateway"},
+	}
+	assert.Equal(t, `ID:            12345678
+URL:           https://example.com/webhook
+Active:        yes
+Events:        push, pull_request
+Content type:  json
+Insecure SSL:  no
+Last response: 502 failed (Bad Gateway)
+Updated:       2019-06-03T00:57:16Z`, formatHookDetails(hook))
+}
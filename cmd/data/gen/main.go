@@ -0,0 +1,213 @@
+// Command gen generates the event catalog embedded in gh-hook, data/events.json,
+// from a release of octokit/webhooks. The index of octokit/webhooks has the
+// description, actions and example payloads of each event, but not the kinds of
+// webhooks that can subscribe to it, nor the GitHub Enterprise Server versions
+// that send it. Those are maintained by hand in data/availability.json, along
+// with the events only sent by GitHub Enterprise Server.
+//
+// It is run from the cmd directory with:
+//
+//	go generate ./cmd
+//
+// See: https://github.com/octokit/webhooks
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	latestReleaseURL = "https://api.github.com/repos/octokit/webhooks/releases/latest"
+	indexURL         = "https://raw.githubusercontent.com/octokit/webhooks/%s/payload-examples/api.github.com/index.json"
+	// maxExamples bounds the size of the catalog, as example payloads embed
+	// full repository and user objects.
+	maxExamples = 1
+)
+
+// indexEvent is an event of the octokit/webhooks index.
+type indexEvent struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Actions     []string          `json:"actions"`
+	Examples    []json.RawMessage `json:"examples"`
+}
+
+// availability is the hand-maintained part of a catalog event. Events that are
+// only sent to global webhooks of GitHub Enterprise Server are missing from the
+// index, so their description and actions are maintained by hand too.
+type availability struct {
+	Availability []string `json:"availability"`
+	GHES         string   `json:"ghes,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Actions      []string `json:"actions,omitempty"`
+}
+
+// event and catalog mirror the format of the catalog read by gh-hook.
+type event struct {
+	Name         string            `json:"name"`
+	Description  string            `json:"description,omitempty"`
+	Actions      []string          `json:"actions,omitempty"`
+	Availability []string          `json:"availability,omitempty"`
+	GHES         string            `json:"ghes,omitempty"`
+	Examples     []json.RawMessage `json:"examples,omitempty"`
+}
+
+type catalog struct {
+	Version string  `json:"version"`
+	Events  []event `json:"events"`
+}
+
+func main() {
+	tag := flag.String("tag", "", "The octokit/webhooks release to generate the catalog from. Defaults to the latest release.")
+	index := flag.String("index", "", "Read the index from this file instead of downloading it. Needs --tag.")
+	overlay := flag.String("availability", "data/availability.json", "The file listing the availability of each event.")
+	output := flag.String("o", "data/events.json", "The file to write the catalog to.")
+	flag.Parse()
+
+	if err := run(*tag, *index, *overlay, *output); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(tag, index, overlay, output string) error {
+	client := &http.Client{Timeout: 30 * time.Second}
+	if tag == "" {
+		if index != "" {
+			return fmt.Errorf("--index needs the release it was taken from, given with --tag")
+		}
+		var err error
+		tag, err = latestRelease(client)
+		if err != nil {
+			return fmt.Errorf("could not get the latest release of octokit/webhooks: %w", err)
+		}
+	}
+	var data []byte
+	var err error
+	if index != "" {
+		data, err = os.ReadFile(index)
+	} else {
+		data, err = download(client, fmt.Sprintf(indexURL, tag))
+	}
+	if err != nil {
+		return fmt.Errorf("could not read the index of octokit/webhooks %s: %w", tag, err)
+	}
+	var events []indexEvent
+	if err := json.Unmarshal(data, &events); err != nil {
+		return fmt.Errorf("could not parse the index of octokit/webhooks %s: %w", tag, err)
+	}
+
+	data, err = os.ReadFile(overlay)
+	if err != nil {
+		return err
+	}
+	var available map[string]availability
+	if err := json.Unmarshal(data, &available); err != nil {
+		return fmt.Errorf("could not parse %s: %w", overlay, err)
+	}
+
+	c, err := generate(tag, events, available)
+	if err != nil {
+		return err
+	}
+	data, err = json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(output, append(data, '\n'), 0644)
+}
+
+// generate combines the index with the availability of each event. Events that
+// no kind of webhook managed by gh-hook can subscribe to, such as sponsorship,
+// which is sent to sponsors listings, are left out. Events missing from the
+// availability file are an error, so that new events get classified. Events
+// described in the availability file but missing from the index are added
+// after the others, in order of name.
+func generate(tag string, events []indexEvent, available map[string]availability) (catalog, error) {
+	c := catalog{Version: tag}
+	var unknown []string
+	indexed := map[string]bool{}
+	for _, e := range events {
+		indexed[e.Name] = true
+		a, ok := available[e.Name]
+		if !ok {
+			unknown = append(unknown, e.Name)
+			continue
+		}
+		if len(a.Availability) == 0 {
+			continue
+		}
+		examples := e.Examples
+		if len(examples) > maxExamples {
+			examples = examples[:maxExamples]
+		}
+		c.Events = append(c.Events, event{
+			Name:         e.Name,
+			Description:  e.Description,
+			Actions:      e.Actions,
+			Availability: a.Availability,
+			GHES:         a.GHES,
+			Examples:     examples,
+		})
+	}
+	var extra []string
+	for name, a := range available {
+		if !indexed[name] && a.Description != "" && len(a.Availability) > 0 {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		a := available[name]
+		c.Events = append(c.Events, event{
+			Name:         name,
+			Description:  a.Description,
+			Actions:      a.Actions,
+			Availability: a.Availability,
+			GHES:         a.GHES,
+		})
+	}
+	if len(unknown) > 0 {
+		return c, fmt.Errorf("add the availability of these events to the availability file: %s", strings.Join(unknown, ", "))
+	}
+	return c, nil
+}
+
+func latestRelease(client *http.Client) (string, error) {
+	data, err := download(client, latestReleaseURL)
+	if err != nil {
+		return "", err
+	}
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.Unmarshal(data, &release); err != nil {
+		return "", err
+	}
+	if release.TagName == "" {
+		return "", fmt.Errorf("no release found")
+	}
+	return release.TagName, nil
+}
+
+func download(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %s from %s", resp.Status, url)
+	}
+	return io.ReadAll(resp.Body)
+}
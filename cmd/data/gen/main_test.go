@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_generate(t *testing.T) {
+	events := []indexEvent{
+		{
+			Name:        "star",
+			Description: "Activity related to a repository being starred.",
+			Actions:     []string{"created", "deleted"},
+			Examples:    []json.RawMessage{json.RawMessage(`{"action":"created"}`), json.RawMessage(`{"action":"deleted"}`)},
+		},
+		{Name: "sponsorship", Description: "Activity related to a sponsorship listing."},
+	}
+	available := map[string]availability{
+		"star":        {Availability: []string{"repository", "organization", "app"}},
+		"sponsorship": {},
+		"user": {
+			Availability: []string{"enterprise"},
+			Description:  "A user account was created or deleted.",
+			Actions:      []string{"created", "deleted"},
+		},
+	}
+	got, err := generate("v7.0.0", events, available)
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	assert.Equal(t, catalog{
+		Version: "v7.0.0",
+		Events: []event{
+			{
+				Name:         "star",
+				Description:  "Activity related to a repository being starred.",
+				Actions:      []string{"created", "deleted"},
+				Availability: []string{"repository", "organization", "app"},
+				Examples:     []json.RawMessage{json.RawMessage(`{"action":"created"}`)},
+			},
+			{
+				Name:         "user",
+				Description:  "A user account was created or deleted.",
+				Actions:      []string{"created", "deleted"},
+				Availability: []string{"enterprise"},
+			},
+		},
+	}, got)
+
+	_, err = generate("v7.0.0", append(events, indexEvent{Name: "brand_new_event"}), available)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "brand_new_event")
+	}
+}
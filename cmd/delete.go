@@ -2,68 +2,107 @@ package cmd
 
 import (
 	"fmt"
-	"strings"
 
-	"github.com/cli/go-gh"
-	"github.com/cli/go-gh/pkg/api"
-	"github.com/cli/go-gh/pkg/repository"
+	"github.com/cli/go-gh/pkg/term"
 	"github.com/lucasmelin/gh-hook/tui"
 	"github.com/spf13/cobra"
 )
 
 func NewCmdDelete() *cobra.Command {
 	var deleteCmd = &cobra.Command{
-		Use:   "delete",
-		Short: "Delete repository webhooks.",
+		Use:          "delete [<id>...]",
+		Short:        "Delete repository webhooks.",
+		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			repo, err := getRepo(cmd)
+			target, err := getTarget(cmd)
 			if err != nil {
 				return err
 			}
+			if err := checkTarget(target, "delete"); err != nil {
+				return err
+			}
+
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			yes, _ := cmd.Flags().GetBool("yes")
 
-			response, err := getWebhooks(repo)
+			response, err := getWebhooks(target)
 			if err != nil {
 				return fmt.Errorf("could not get webhooks: %w\n", err)
 			}
 			choices := formatHookChoices(response)
 			if len(choices) == 0 {
-				fmt.Printf("%s/%s has no webhooks\n", repo.Owner(), repo.Name())
+				fmt.Printf("%s has no webhooks\n", targetName(target))
 				return nil
 			}
 
-			hooksToDelete, err := tui.ChooseMany("Which webhooks would you like to delete?", choices)
+			filter := filterFromFlags(cmd)
+			hookIds := args
+			switch {
+			case len(hookIds) > 0 && filter.isSet():
+				return fmt.Errorf("webhook IDs cannot be combined with filters\n")
+			case len(hookIds) > 0:
+			case filter.isSet():
+				hookIds = idsOfHooks(filterHooks(response, filter))
+				if len(hookIds) == 0 {
+					fmt.Println("No webhooks match the filters")
+					return nil
+				}
+			default:
+				hooksToDelete, err := tui.ChooseMany("Which webhooks would you like to delete?", choices)
+				if err != nil {
+					return fmt.Errorf("could not choose webhooks: %w", err)
+				}
+				hookIds = hookIdsFromChoices(hooksToDelete)
+			}
+			hooks, err := selectHooks(response, hookIds)
 			if err != nil {
-				return fmt.Errorf("could not choose webhooks: %w", err)
+				return err
 			}
-			var deleteIds []string
-			for _, hook := range hooksToDelete {
-				_, withoutPrefix, _ := strings.Cut(hook, " ")
-				delId, _, _ := strings.Cut(withoutPrefix, " ")
-				deleteIds = append(deleteIds, delId)
+			confirmed, err := confirmHooks(term.FromEnv(), "delete", hooks, dryRun, yes)
+			if err != nil || !confirmed {
+				return err
 			}
-
-			return deleteHooks(repo, deleteIds)
+			return deleteHooks(target, hookIds)
 		},
 	}
+	addFilterFlags(deleteCmd, "delete")
+	deleteCmd.Flags().Bool("dry-run", false, "Show the webhooks that would be deleted without deleting them.")
+	deleteCmd.Flags().BoolP("yes", "y", false, "Delete the webhooks without asking for confirmation.")
+	addTargetFlags(deleteCmd)
 	return deleteCmd
 }
 
-func deleteHooks(repo repository.Repository, deleteIds []string) error {
-	hookOpts := api.ClientOptions{
-		Host: repo.Host(),
-	}
-	client, err := gh.RESTClient(&hookOpts)
-	if err != nil {
-		return err
-	}
+func deleteHooks(target hookTarget, deleteIds []string) error {
 	for _, hookId := range deleteIds {
 		fmt.Printf("Deleting %s\n", hookId)
-		apiUrl := fmt.Sprintf("repos/%s/%s/hooks/%s", repo.Owner(), repo.Name(), hookId)
-		err = client.Delete(apiUrl, nil)
-		if err != nil {
+		if err := deleteHook(target, hookId); err != nil {
 			return err
 		}
 	}
 	fmt.Printf("Deleted %d hooks 🗑️\n", len(deleteIds))
 	return nil
 }
+
+func deleteHook(target hookTarget, hookId string) error {
+	if err := checkTarget(target, "delete"); err != nil {
+		return err
+	}
+	client, err := restClient(target)
+	if err != nil {
+		return err
+	}
+	before, err := getWebhook(target, hookId)
+	if err != nil {
+		return fmt.Errorf("could not get webhook %s: %w\n", hookId, err)
+	}
+	// The history is the only copy restore can recreate the hook from.
+	if err := checkHistory(); err != nil {
+		return fmt.Errorf("could not record webhook %s in the history before deleting it: %w\n", hookId, err)
+	}
+	apiUrl := hookPath(target, hookId)
+	if err := client.Delete(apiUrl, nil); err != nil {
+		return err
+	}
+	recordHistory(target, "delete", &before, nil)
+	return nil
+}
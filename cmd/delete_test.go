@@ -24,6 +24,10 @@ func Test_deleteHooks(t *testing.T) {
 				owner: "lucasmelin",
 			},
 			httpMocks: func() {
+				gock.New("https://api.github.com").
+					Get("repos/lucasmelin/test-repo/hooks/12365678").
+					Reply(200).
+					JSON(`{"id": 12365678, "active": true, "events": ["push"], "config": {"url": "https://example.com/webhook"}}`)
 				gock.New("https://api.github.com").
 					Delete("repos/lucasmelin/test-repo/hooks/12365678").
 					Reply(204)
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+)
+
+// Delivery is an attempt to deliver an event to a webhook.
+type Delivery struct {
+	Id          int     `json:"id"`
+	Guid        string  `json:"guid"`
+	DeliveredAt string  `json:"delivered_at"`
+	Redelivery  bool    `json:"redelivery"`
+	Duration    float64 `json:"duration"`
+	Status      string  `json:"status"`
+	StatusCode  int     `json:"status_code"`
+	Event       string  `json:"event"`
+	Action      string  `json:"action,omitempty"`
+}
+
+// succeeded reports whether the receiver responded with a 2xx status code.
+func (d Delivery) succeeded() bool {
+	return d.StatusCode >= 200 && d.StatusCode < 300
+}
+
+func formatDelivery(d Delivery) string {
+	mark := "✓"
+	if !d.succeeded() {
+		mark = "✗"
+	}
+	event := d.Event
+	if d.Action != "" {
+		event += "." + d.Action
+	}
+	return fmt.Sprintf("%s %s %s %d %.2fs", mark, d.DeliveredAt, event, d.StatusCode, d.Duration)
+}
+
+// getDeliveries returns the most recent deliveries of a hook, newest first.
+func getDeliveries(target hookTarget, hookId string, perPage int) ([]Delivery, error) {
+	if err := checkTarget(target, "deliveries"); err != nil {
+		return nil, err
+	}
+	client, err := restClient(target)
+	if err != nil {
+		return nil, err
+	}
+	response := []Delivery{}
+	apiUrl := fmt.Sprintf("%s/deliveries?per_page=%d", hookPath(target, hookId), perPage)
+	if err := client.Get(apiUrl, &response); err != nil {
+		return nil, explainUnsupported(target.Host(), featureDeliveries, err)
+	}
+	return response, nil
+}
+
+// nextLinkPattern finds the URL of the next page in a Link header.
+var nextLinkPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// getDeliveriesPage returns a page of deliveries of a hook, newest first,
+// starting at cursor, and the cursor of the next page of older deliveries. The
+// next cursor is empty on the last page.
+func getDeliveriesPage(target hookTarget, hookId string, perPage int, cursor string) ([]Delivery, string, error) {
+	if err := checkTarget(target, "deliveries"); err != nil {
+		return nil, "", err
+	}
+	client, err := restClient(target)
+	if err != nil {
+		return nil, "", err
+	}
+	apiUrl := fmt.Sprintf("%s/deliveries?per_page=%d", hookPath(target, hookId), perPage)
+	if cursor != "" {
+		apiUrl += "&cursor=" + url.QueryEscape(cursor)
+	}
+	resp, err := client.Request(http.MethodGet, apiUrl, nil)
+	if err != nil {
+		return nil, "", explainUnsupported(target.Host(), featureDeliveries, err)
+	}
+	defer resp.Body.Close()
+	response := []Delivery{}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, "", fmt.Errorf("could not parse deliveries: %w", err)
+	}
+	var next string
+	if m := nextLinkPattern.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+		if nextUrl, err := url.Parse(m[1]); err == nil {
+			next = nextUrl.Query().Get("cursor")
+		}
+	}
+	return response, next, nil
+}
+
+func redeliver(target hookTarget, hookId string, deliveryId string) error {
+	if err := checkTarget(target, "deliveries"); err != nil {
+		return err
+	}
+	client, err := restClient(target)
+	if err != nil {
+		return err
+	}
+	apiUrl := fmt.Sprintf("%s/deliveries/%s/attempts", hookPath(target, hookId), deliveryId)
+	if err := client.Post(apiUrl, nil, nil); err != nil {
+		return fmt.Errorf("could not redeliver %s: %w", deliveryId, explainUnsupported(target.Host(), featureDeliveries, err))
+	}
+	return nil
+}
+
+func pingHook(target hookTarget, hookId string) error {
+	if err := checkTarget(target, "ping"); err != nil {
+		return err
+	}
+	client, err := restClient(target)
+	if err != nil {
+		return err
+	}
+	apiUrl := hookPath(target, hookId) + "/pings"
+	if err := client.Post(apiUrl, nil, nil); err != nil {
+		return fmt.Errorf("could not ping hook %s: %w", hookId, err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/cli/go-gh/pkg/repository"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_getDeliveries(t *testing.T) {
+	tests := []struct {
+		name      string
+		repo      repository.Repository
+		hookId    string
+		httpMocks func()
+		want      []Delivery
+		wantErr   bool
+	}{
+		{
+			name: "success request",
+			repo: MockRepo{
+				host:  "github.com",
+				name:  "Hello-World",
+				owner: "octocat",
+			},
+			hookId: "12345678",
+			httpMocks: func() {
+				gock.New("https://api.github.com").
+					Get("repos/octocat/Hello-World/hooks/12345678/deliveries").
+					MatchParam("per_page", "20").
+					Reply(200).
+					JSON(`[
+  {
+    "id": 12345678,
+    "guid": "0b989ba4-242f-11e5-81e1-c7b6966d2516",
+    "delivered_at": "2019-06-03T00:57:16Z",
+    "redelivery": false,
+    "duration": 0.27,
+    "status": "OK",
+    "status_code": 200,
+    "event": "issues",
+    "action": "opened",
+    "installation_id": 123,
+    "repository_id": 456
+  }
+]`)
+			},
+			want: []Delivery{
+				{
+					Id:          12345678,
+					Guid:        "0b989ba4-242f-11e5-81e1-c7b6966d2516",
+					DeliveredAt: "2019-06-03T00:57:16Z",
+					Duration:    0.27,
+					Status:      "OK",
+					StatusCode:  200,
+					Event:       "issues",
+					Action:      "opened",
+				},
+			},
+		},
+		{
+			name: "hook not found",
+			repo: MockRepo{
+				host:  "github.com",
+				name:  "Hello-World",
+				owner: "octocat",
+			},
+			hookId: "1",
+			httpMocks: func() {
+				gock.New("https://api.github.com").
+					Get("repos/octocat/Hello-World/hooks/1/deliveries").
+					Reply(404).
+					JSON(`{"message": "Not Found"}`)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubConfig(t, testConfig())
+			t.Cleanup(gock.Off)
+			if tt.httpMocks != nil {
+				tt.httpMocks()
+			}
+			got, err := getDeliveries(tt.repo, tt.hookId, recentDeliveries)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getDeliveries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+		})
+	}
+}
+
+func Test_redeliver(t *testing.T) {
+	stubConfig(t, testConfig())
+	t.Cleanup(gock.Off)
+	gock.New("https://api.github.com").
+		Post("repos/octocat/Hello-World/hooks/12345678/deliveries/42/attempts").
+		Reply(202).
+		JSON(`{}`)
+	repo := MockRepo{host: "github.com", name: "Hello-World", owner: "octocat"}
+	if err := redeliver(repo, "12345678", "42"); err != nil {
+		t.Fatalf("redeliver() error = %v", err)
+	}
+	assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+}
+
+func Test_pingHook(t *testing.T) {
+	stubConfig(t, testConfig())
+	t.Cleanup(gock.Off)
+	gock.New("https://api.github.com").
+		Post("repos/octocat/Hello-World/hooks/12345678/pings").
+		Reply(204)
+	repo := MockRepo{host: "github.com", name: "Hello-World", owner: "octocat"}
+	if err := pingHook(repo, "12345678"); err != nil {
+		t.Fatalf("pingHook() error = %v", err)
+	}
+	assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+}
+
+func Test_formatDelivery(t *testing.T) {
+	tests := []struct {
+		name     string
+		delivery Delivery
+		want     string
+	}{
+		{
+			name:     "successful delivery",
+			delivery: Delivery{DeliveredAt: "2019-06-03T00:57:16Z", Event: "issues", Action: "opened", StatusCode: 200, Duration: 0.27},
+			want:     "✓ 2019-06-03T00:57:16Z issues.opened 200 0.27s",
+		},
+		{
+			name:     "failed delivery",
+			delivery: Delivery{DeliveredAt: "2019-06-03T00:57:16Z", Event: "push", StatusCode: 502, Duration: 10},
+			want:     "✗ 2019-06-03T00:57:16Z push 502 10.00s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatDelivery(tt.delivery))
+		})
+	}
+}
+
+func Test_getDeliveriesPage(t *testing.T) {
+	stubConfig(t, testConfig())
+	t.Cleanup(gock.Off)
+	repo := MockRepo{host: "github.com", name: "test-repo", owner: "lucasmelin"}
+	gock.New("https://api.github.com").
+		Get("repos/lucasmelin/test-repo/hooks/1/deliveries").
+		MatchParam("per_page", "2").
+		MatchParam("cursor", "v1_10").
+		Reply(200).
+		SetHeader("Link", `<https://api.github.com/repositories/1/hooks/1/deliveries?per_page=2&cursor=v1_8>; rel="next"`).
+		JSON(`[{"id": 10, "status_code": 200}, {"id": 9, "status_code": 500}]`)
+
+	deliveries, next, err := getDeliveriesPage(repo, "1", 2, "v1_10")
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+	assert.Equal(t, "v1_8", next)
+	assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+}
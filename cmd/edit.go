@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/lucasmelin/gh-hook/tui"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEdit() *cobra.Command {
+	var editCmd = &cobra.Command{
+		Use:          "edit [<id>]",
+		Short:        "Edit a repository webhook.",
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := getTarget(cmd)
+			if err != nil {
+				return err
+			}
+
+			var hookId string
+			if len(args) > 0 {
+				hookId = args[0]
+			} else {
+				currentHooks, err := getWebhooks(target)
+				if err != nil {
+					return fmt.Errorf("could not get webhooks: %w\n", err)
+				}
+				if len(currentHooks) == 0 {
+					fmt.Printf("%s has no webhooks\n", targetName(target))
+					return nil
+				}
+				choice, err := tui.ChooseOne("Which webhook would you like to edit?", formatHookChoices(currentHooks))
+				if err != nil {
+					return fmt.Errorf("could not choose webhook: %w", err)
+				}
+				hookId = hookIdsFromChoices([]string{choice})[0]
+			}
+			var opts editOptions
+			opts.dryRun, _ = cmd.Flags().GetBool("dry-run")
+			opts.force, _ = cmd.Flags().GetBool("force")
+			opts.policy, _ = cmd.Flags().GetString("policy")
+			return editFromPrompt(target, hookId, opts)
+		},
+	}
+	editCmd.Flags().Bool("dry-run", false, "Show the updated webhook without saving it.")
+	editCmd.Flags().Bool("force", false, "Save the webhook even if it violates the policy.")
+	editCmd.Flags().String("policy", "", "Check the webhook against this policy file instead of gh-hook/policy.json in the gh config directory.")
+	addTargetFlags(editCmd)
+	return editCmd
+}
+
+// editOptions are the flags of the edit command.
+type editOptions struct {
+	// dryRun only shows the update.
+	dryRun bool
+	// force saves hooks that violate the policy.
+	force bool
+	// policy is the policy file, or empty for the default one.
+	policy string
+}
+
+// editFromPrompt prompts for the new settings of a hook, starting from its
+// current ones, and updates it.
+func editFromPrompt(target hookTarget, hookId string, opts editOptions) error {
+	current, err := getWebhook(target, hookId)
+	if err != nil {
+		return fmt.Errorf("could not get webhook: %w\n", err)
+	}
+	events, err := getEvents(false, targetKind(target), target.Host())
+	if err != nil {
+		return fmt.Errorf("could not get events: %w\n", err)
+	}
+	_, configOnly := target.(appTarget)
+	updated, err := hookFromEditPrompt(events, current, configOnly)
+	if err != nil {
+		return err
+	}
+	// An empty secret keeps the current one, which the policy should see.
+	checked := updated
+	if checked.Config.Secret == "" {
+		checked.Config.Secret = current.Config.Secret
+	}
+	if err := enforcePolicy(opts.policy, opts.force, checked); err != nil {
+		return err
+	}
+	if opts.dryRun {
+		fmt.Printf("Would update hook %s:\n", hookId)
+		updated.Id = current.Id
+		return printHookJSON(os.Stdout, updated)
+	}
+	if err := updateHook(target, hookId, updated); err != nil {
+		return err
+	}
+	fmt.Printf("Updated hook %s ✏️\n", hookId)
+	return nil
+}
+
+// hookFromEditPrompt shows the form for the settings of an existing hook. When
+// configOnly is set, as for app webhooks, the events and state are not shown.
+func hookFromEditPrompt(events []Event, current Hook, configOnly bool) (Hook, error) {
+	title := fmt.Sprintf("Edit webhook %d", current.Id)
+	return hookFromForm(title, events, current, true, "leave empty to keep the current secret", configOnly)
+}
+
+// hookUpdate is the body of a request updating a hook. Unlike Hook, it always
+// sends active, so that hooks can be deactivated.
+type hookUpdate struct {
+	Active bool     `json:"active"`
+	Events []string `json:"events,omitempty"`
+	// Config is only sent for global webhooks, which have no separate
+	// configuration endpoint.
+	Config *HookConfig `json:"config,omitempty"`
+}
+
+// updateHook replaces the settings of a hook. The configuration is updated
+// separately, so that an empty secret keeps the current one. Global webhooks
+// take their configuration along with the other settings, and only the
+// configuration of an app webhook can be changed.
+func updateHook(target hookTarget, hookId string, data Hook) error {
+	client, err := restClient(target)
+	if err != nil {
+		return fmt.Errorf("error creating REST client: %w\n", err)
+	}
+	before, err := getWebhook(target, hookId)
+	if err != nil {
+		return fmt.Errorf("could not get webhook: %w\n", err)
+	}
+
+	apiUrl := hookPath(target, hookId)
+	_, isApp := target.(appTarget)
+	_, isEnterprise := target.(enterpriseTarget)
+	if add, remove := diffEvents(before.Events, data.Events); isApp && (data.Active != before.Active || len(add) > 0 || len(remove) > 0) {
+		return fmt.Errorf("the events and state of app webhooks are app settings, only the configuration can be edited\n")
+	}
+	if !isApp {
+		update := hookUpdate{Active: data.Active, Events: data.Events}
+		if isEnterprise {
+			// The configuration is replaced as a whole, so sending it without
+			// the secret would remove the current one.
+			switch {
+			case data.Config.Secret != "" || before.Config.Secret == "":
+				update.Config = &data.Config
+			case !sameConfig(before.Config, data.Config):
+				return fmt.Errorf("global webhooks replace their whole configuration, enter the secret again to change it\n")
+			}
+		}
+		jsonData, err := json.Marshal(update)
+		if err != nil {
+			return fmt.Errorf("could not convert hook to JSON: %w\n", err)
+		}
+		if err := client.Patch(apiUrl, bytes.NewBuffer(jsonData), nil); err != nil {
+			return fmt.Errorf("could not update webhook: %w\n", err)
+		}
+	}
+	if !isEnterprise {
+		jsonData, err := json.Marshal(data.Config)
+		if err != nil {
+			return fmt.Errorf("could not convert config to JSON: %w\n", err)
+		}
+		if err := client.Patch(apiUrl+"/config", bytes.NewBuffer(jsonData), nil); err != nil {
+			return fmt.Errorf("could not update webhook configuration: %w\n", err)
+		}
+	}
+
+	after := before
+	after.Config = data.Config
+	if data.Config.Secret == "" {
+		after.Config.Secret = before.Config.Secret
+	}
+	if !isApp {
+		after.Active = data.Active
+		after.Events = data.Events
+	}
+	recordHistory(target, "update", &before, &after)
+	return nil
+}
+
+func setHookActive(target hookTarget, hookId string, active bool) error {
+	action := "disable"
+	if active {
+		action = "enable"
+	}
+	if err := checkTarget(target, action); err != nil {
+		return err
+	}
+	client, err := restClient(target)
+	if err != nil {
+		return fmt.Errorf("error creating REST client: %w\n", err)
+	}
+	before, err := getWebhook(target, hookId)
+	if err != nil {
+		return fmt.Errorf("could not get webhook: %w\n", err)
+	}
+	jsonData, err := json.Marshal(hookUpdate{Active: active})
+	if err != nil {
+		return fmt.Errorf("could not convert hook to JSON: %w\n", err)
+	}
+	after := Hook{}
+	apiUrl := hookPath(target, hookId)
+	if err := client.Patch(apiUrl, bytes.NewBuffer(jsonData), &after); err != nil {
+		return fmt.Errorf("could not update webhook: %w\n", err)
+	}
+	recordHistory(target, action, &before, &after)
+	return nil
+}
+
+// sameConfig reports whether two configurations deliver the same way, leaving
+// out their secrets.
+func sameConfig(a, b HookConfig) bool {
+	return a.Url == b.Url && a.ContentType == b.ContentType && (a.InsecureSSL == "1") == (b.InsecureSSL == "1")
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/cli/go-gh/pkg/repository"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_updateHook(t *testing.T) {
+	tests := []struct {
+		name      string
+		repo      repository.Repository
+		hookId    string
+		data      Hook
+		httpMocks func()
+		wantErr   bool
+	}{
+		{
+			name: "deactivate and keep secret",
+			repo: MockRepo{
+				host:  "github.com",
+				name:  "test-repo",
+				owner: "lucasmelin",
+			},
+			hookId: "12345678",
+			data: Hook{
+				Active: false,
+				Events: []string{"push"},
+				Config: HookConfig{
+					Url:         "https://example.com/webhook",
+					ContentType: "json",
+					InsecureSSL: "0",
+				},
+			},
+			httpMocks: func() {
+				gock.New("https://api.github.com").
+					Get("repos/lucasmelin/test-repo/hooks/12345678").
+					Reply(200).
+					JSON(`{"id": 12345678, "active": true, "events": ["push"], "config": {"url": "https://example.com/webhook"}}`)
+				gock.New("https://api.github.com").
+					Patch("repos/lucasmelin/test-repo/hooks/12345678").
+					BodyString(`{"active":false,"events":["push"]}`).
+					Reply(200).
+					JSON(`{"id": 12345678}`)
+				gock.New("https://api.github.com").
+					Patch("repos/lucasmelin/test-repo/hooks/12345678/config").
+					BodyString(`{"url":"https://example.com/webhook","content_type":"json","insecure_ssl":"0"}`).
+					Reply(200).
+					JSON(`{"url": "https://example.com/webhook"}`)
+			},
+		},
+		{
+			name: "update fails",
+			repo: MockRepo{
+				host:  "github.com",
+				name:  "test-repo",
+				owner: "lucasmelin",
+			},
+			hookId: "1",
+			httpMocks: func() {
+				gock.New("https://api.github.com").
+					Get("repos/lucasmelin/test-repo/hooks/1").
+					Reply(404).
+					JSON(`{"message": "Not Found"}`)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubConfig(t, testConfig())
+			t.Cleanup(gock.Off)
+			if tt.httpMocks != nil {
+				tt.httpMocks()
+			}
+			err := updateHook(tt.repo, tt.hookId, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("updateHook(%v, %v, %v) error = %v, wantErr %v", tt.repo, tt.hookId, tt.data, err, tt.wantErr)
+			}
+			assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cli/go-gh/pkg/term"
+	"github.com/lucasmelin/gh-hook/tui"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEnable() *cobra.Command {
+	return newCmdSetActive(true)
+}
+
+func NewCmdDisable() *cobra.Command {
+	return newCmdSetActive(false)
+}
+
+// newCmdSetActive builds the enable and disable commands, which only change
+// whether hooks are active.
+func newCmdSetActive(active bool) *cobra.Command {
+	action, short, yesUsage := "disable", "Pause repository webhooks without deleting them.", "Disable the webhooks without asking for confirmation."
+	if active {
+		action, short, yesUsage = "enable", "Resume paused repository webhooks.", "Enable the webhooks without asking for confirmation."
+	}
+	var setActiveCmd = &cobra.Command{
+		Use:          action + " [<id>...]",
+		Short:        short,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := getTarget(cmd)
+			if err != nil {
+				return err
+			}
+			if err := checkTarget(target, action); err != nil {
+				return err
+			}
+
+			all, _ := cmd.Flags().GetBool("all")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			yes, _ := cmd.Flags().GetBool("yes")
+
+			currentHooks, err := getWebhooks(target)
+			if err != nil {
+				return fmt.Errorf("could not get webhooks: %w\n", err)
+			}
+			if len(currentHooks) == 0 {
+				fmt.Printf("%s has no webhooks\n", targetName(target))
+				return nil
+			}
+			filter := filterFromFlags(cmd)
+			hookIds := args
+			switch {
+			case len(hookIds) > 0 && (all || filter.isSet()):
+				return fmt.Errorf("webhook IDs cannot be combined with --all or filters\n")
+			case len(hookIds) > 0:
+			case all || filter.isSet():
+				hookIds = idsOfHooks(filterHooks(currentHooks, filter))
+				if len(hookIds) == 0 {
+					fmt.Println("No webhooks match the filters")
+					return nil
+				}
+			default:
+				// Only offer the hooks that would change.
+				var candidates []Hook
+				for _, hook := range currentHooks {
+					if hook.Active != active {
+						candidates = append(candidates, hook)
+					}
+				}
+				if len(candidates) == 0 {
+					fmt.Printf("Every webhook of %s is already %sd\n", targetName(target), action)
+					return nil
+				}
+				choices, err := tui.ChooseMany(fmt.Sprintf("Which webhooks would you like to %s?", action), formatHookChoices(candidates))
+				if err != nil {
+					return fmt.Errorf("could not choose webhooks: %w", err)
+				}
+				hookIds = hookIdsFromChoices(choices)
+			}
+			hooks, err := selectHooks(currentHooks, hookIds)
+			if err != nil {
+				return err
+			}
+			confirmed, err := confirmHooks(term.FromEnv(), action, hooks, dryRun, yes)
+			if err != nil || !confirmed {
+				return err
+			}
+			return setHooksActive(target, hookIds, active)
+		},
+	}
+	setActiveCmd.Flags().Bool("all", false, "Apply to every webhook in the repository, or every one matching the filters, instead of choosing them.")
+	addFilterFlags(setActiveCmd, action)
+	setActiveCmd.Flags().Bool("dry-run", false, fmt.Sprintf("Show the webhooks that would be %sd without changing them.", action))
+	setActiveCmd.Flags().BoolP("yes", "y", false, yesUsage)
+	addTargetFlags(setActiveCmd)
+	return setActiveCmd
+}
+
+// setHooksActive enables or disables each hook, leaving the rest of its
+// settings untouched.
+func setHooksActive(target hookTarget, hookIds []string, active bool) error {
+	for _, hookId := range hookIds {
+		if err := setHookActive(target, hookId, active); err != nil {
+			return err
+		}
+	}
+	if active {
+		fmt.Printf("Enabled %d hooks ▶️\n", len(hookIds))
+	} else {
+		fmt.Printf("Disabled %d hooks ⏸️\n", len(hookIds))
+	}
+	return nil
+}
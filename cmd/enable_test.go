@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/cli/go-gh/pkg/repository"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_setHooksActive(t *testing.T) {
+	tests := []struct {
+		name      string
+		repo      repository.Repository
+		hookIds   []string
+		active    bool
+		httpMocks func()
+		wantErr   bool
+	}{
+		{
+			name: "disable sends active false",
+			repo: MockRepo{
+				host:  "github.com",
+				name:  "test-repo",
+				owner: "lucasmelin",
+			},
+			hookIds: []string{"1", "2"},
+			active:  false,
+			httpMocks: func() {
+				gock.New("https://api.github.com").
+					Get("repos/lucasmelin/test-repo/hooks/1").
+					Reply(200).
+					JSON(`{"id": 1, "active": true, "events": ["push"], "config": {"url": "https://example.com/webhook"}}`)
+				gock.New("https://api.github.com").
+					Patch("repos/lucasmelin/test-repo/hooks/1").
+					BodyString(`{"active":false}`).
+					Reply(200).
+					JSON(`{"id": 1, "active": false}`)
+				gock.New("https://api.github.com").
+					Get("repos/lucasmelin/test-repo/hooks/2").
+					Reply(200).
+					JSON(`{"id": 2, "active": true, "events": ["push"], "config": {"url": "https://example.com/webhook"}}`)
+				gock.New("https://api.github.com").
+					Patch("repos/lucasmelin/test-repo/hooks/2").
+					BodyString(`{"active":false}`).
+					Reply(200).
+					JSON(`{"id": 2, "active": false}`)
+			},
+		},
+		{
+			name: "enable",
+			repo: MockRepo{
+				host:  "github.com",
+				name:  "test-repo",
+				owner: "lucasmelin",
+			},
+			hookIds: []string{"1"},
+			active:  true,
+			httpMocks: func() {
+				gock.New("https://api.github.com").
+					Get("repos/lucasmelin/test-repo/hooks/1").
+					Reply(200).
+					JSON(`{"id": 1, "active": true, "events": ["push"], "config": {"url": "https://example.com/webhook"}}`)
+				gock.New("https://api.github.com").
+					Patch("repos/lucasmelin/test-repo/hooks/1").
+					BodyString(`{"active":true}`).
+					Reply(200).
+					JSON(`{"id": 1, "active": true}`)
+			},
+		},
+		{
+			name: "unknown hook",
+			repo: MockRepo{
+				host:  "github.com",
+				name:  "test-repo",
+				owner: "lucasmelin",
+			},
+			hookIds: []string{"3"},
+			httpMocks: func() {
+				gock.New("https://api.github.com").
+					Get("repos/lucasmelin/test-repo/hooks/3").
+					Reply(404).
+					JSON(`{"message": "Not Found"}`)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubConfig(t, testConfig())
+			t.Cleanup(gock.Off)
+			if tt.httpMocks != nil {
+				tt.httpMocks()
+			}
+			err := setHooksActive(tt.repo, tt.hookIds, tt.active)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setHooksActive(%v, %v, %v) error = %v, wantErr %v", tt.repo, tt.hookIds, tt.active, err, tt.wantErr)
+			}
+			assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+const (
+	eventIndexURL = "https://octokit.github.io/webhooks/payload-examples/api.github.com/index.json"
+	// eventCacheTTL is how long a downloaded event list is used before checking
+	// for a newer one.
+	eventCacheTTL = 24 * time.Hour
+	// eventFetchTimeout bounds the download so that being offline does not
+	// block webhook creation.
+	eventFetchTimeout = 10 * time.Second
+)
+
+// eventCache is the downloaded event list along with the validators needed for
+// conditional requests.
+type eventCache struct {
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	FetchedAt    time.Time `json:"fetched_at"`
+	Events       []Event   `json:"events"`
+}
+
+func (c eventCache) fresh() bool {
+	return len(c.Events) > 0 && time.Since(c.FetchedAt) < eventCacheTTL
+}
+
+// cacheDir mirrors the cache directory used by gh.
+func cacheDir() string {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "gh")
+	}
+	if dir := os.Getenv("LocalAppData"); runtime.GOOS == "windows" && dir != "" {
+		return filepath.Join(dir, "gh")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".cache", "gh")
+}
+
+func eventCachePath() string {
+	return filepath.Join(cacheDir(), "gh-hook", "events.json")
+}
+
+func readEventCache() (eventCache, error) {
+	var c eventCache
+	data, err := os.ReadFile(eventCachePath())
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("could not parse event cache: %w", err)
+	}
+	return c, nil
+}
+
+func writeEventCache(c eventCache) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(eventCachePath()), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(eventCachePath(), data, 0644)
+}
+
+func clearEventCache() error {
+	err := os.Remove(eventCachePath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// refreshEvents returns the downloaded event list, using the cache while it is
+// fresh unless force is set. When the download fails, the cached events, or
+// the embedded catalog if there are none, are used instead with a warning,
+// unless force is set. The events are not filtered by scope.
+func refreshEvents(force bool) ([]Event, error) {
+	cached, cacheErr := readEventCache()
+	if cacheErr == nil && !force && cached.fresh() {
+		return cached.Events, nil
+	}
+	fetched, err := fetchEvents(cached)
+	if err != nil {
+		if force {
+			return nil, fmt.Errorf("could not download events: %w", err)
+		}
+		if cacheErr == nil && len(cached.Events) > 0 {
+			fmt.Fprintf(os.Stderr, "could not download events, using the list cached on %s: %v\n", cached.FetchedAt.Format(time.RFC1123), err)
+			return cached.Events, nil
+		}
+		fmt.Fprintf(os.Stderr, "could not download events, using the built-in list: %v\n", err)
+		return catalog.Events, nil
+	}
+	if err := writeEventCache(fetched); err != nil {
+		fmt.Fprintf(os.Stderr, "could not cache events: %v\n", err)
+	}
+	return fetched.Events, nil
+}
+
+// fetchEvents downloads the octokit/webhooks index. The validators of cached
+// are sent along, so an unchanged index is not downloaded again.
+func fetchEvents(cached eventCache) (eventCache, error) {
+	client := &http.Client{Timeout: eventFetchTimeout}
+	req, err := http.NewRequest("GET", eventIndexURL, nil)
+	if err != nil {
+		return cached, err
+	}
+	req.Header.Add("Accept", "application/json")
+	if len(cached.Events) > 0 {
+		if cached.ETag != "" {
+			req.Header.Add("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Add("If-Modified-Since", cached.LastModified)
+		}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return cached, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	if resp.StatusCode == http.StatusNotModified && len(cached.Events) > 0 {
+		cached.FetchedAt = time.Now()
+		return cached, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return cached, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return cached, err
+	}
+	var events []Event
+	if err := json.Unmarshal(body, &events); err != nil {
+		return cached, err
+	}
+	for i, e := range events {
+		if known, ok := catalogEvent(e.Name); ok {
+			if len(e.Availability) == 0 {
+				events[i].Availability = known.Availability
+			}
+			if e.GHES == "" {
+				events[i].GHES = known.GHES
+			}
+		}
+	}
+	return eventCache{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FetchedAt:    time.Now(),
+		Events:       events,
+	}, nil
+}
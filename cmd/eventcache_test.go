@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_refreshEvents(t *testing.T) {
+	cachedEvents := []Event{{Name: "push"}}
+	downloadedEvents := []Event{
+		{Name: "push", Availability: []string{"repository", "organization", "app"}},
+		{Name: "star", Availability: []string{"repository", "organization", "app"}},
+	}
+	tests := []struct {
+		name      string
+		cache     *eventCache
+		force     bool
+		httpMocks func()
+		want      []Event
+		wantCache eventCache
+		wantErr   bool
+	}{
+		{
+			name:  "fresh cache is used without downloading",
+			cache: &eventCache{FetchedAt: time.Now(), Events: cachedEvents},
+			want:  cachedEvents,
+		},
+		{
+			name:  "forced refresh downloads and caches",
+			cache: &eventCache{FetchedAt: time.Now(), Events: cachedEvents},
+			force: true,
+			httpMocks: func() {
+				gock.New("https://octokit.github.io").
+					Get("webhooks/payload-examples/api.github.com/index.json").
+					Reply(200).
+					SetHeader("ETag", `"abc"`).
+					JSON(`[{"name": "push"}, {"name": "star"}]`)
+			},
+			want:      downloadedEvents,
+			wantCache: eventCache{ETag: `"abc"`, Events: downloadedEvents},
+		},
+		{
+			name:  "stale cache is revalidated",
+			cache: &eventCache{ETag: `"abc"`, FetchedAt: time.Now().Add(-2 * eventCacheTTL), Events: cachedEvents},
+			httpMocks: func() {
+				gock.New("https://octokit.github.io").
+					Get("webhooks/payload-examples/api.github.com/index.json").
+					MatchHeader("If-None-Match", `"abc"`).
+					Reply(304)
+			},
+			want:      cachedEvents,
+			wantCache: eventCache{ETag: `"abc"`, Events: cachedEvents},
+		},
+		{
+			name:  "failed download falls back to the cache",
+			cache: &eventCache{FetchedAt: time.Now().Add(-2 * eventCacheTTL), Events: cachedEvents},
+			httpMocks: func() {
+				gock.New("https://octokit.github.io").
+					Get("webhooks/payload-examples/api.github.com/index.json").
+					ReplyError(errors.New("offline"))
+			},
+			want: cachedEvents,
+		},
+		{
+			name: "failed download without cache falls back to the catalog",
+			httpMocks: func() {
+				gock.New("https://octokit.github.io").
+					Get("webhooks/payload-examples/api.github.com/index.json").
+					Reply(500)
+			},
+			want: catalog.Events,
+		},
+		{
+			name:  "failed forced refresh is an error",
+			cache: &eventCache{FetchedAt: time.Now(), Events: cachedEvents},
+			force: true,
+			httpMocks: func() {
+				gock.New("https://octokit.github.io").
+					Get("webhooks/payload-examples/api.github.com/index.json").
+					ReplyError(errors.New("offline"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_CACHE_HOME", t.TempDir())
+			t.Cleanup(gock.Off)
+			if tt.cache != nil {
+				if err := writeEventCache(*tt.cache); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.httpMocks != nil {
+				tt.httpMocks()
+			}
+			got, err := refreshEvents(tt.force)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("refreshEvents(%v) error = %v, wantErr %v", tt.force, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+			if tt.wantCache.Events != nil {
+				cache, err := readEventCache()
+				if err != nil {
+					t.Fatalf("readEventCache() error = %v", err)
+				}
+				assert.Equal(t, tt.wantCache.ETag, cache.ETag)
+				assert.Equal(t, tt.wantCache.Events, cache.Events)
+				assert.True(t, cache.fresh())
+			}
+			assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+		})
+	}
+}
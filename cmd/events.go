@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/cli/go-gh/pkg/jsonpretty"
+	"github.com/cli/go-gh/pkg/tableprinter"
+	"github.com/cli/go-gh/pkg/term"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEvents() *cobra.Command {
+	var eventsCmd = &cobra.Command{
+		Use:   "events [<name>]",
+		Short: "List the webhook events, or show the details of a single event.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			refresh, _ := cmd.Flags().GetBool("refresh")
+			clearCache, _ := cmd.Flags().GetBool("clear-cache")
+			if clearCache {
+				if err := clearEventCache(); err != nil {
+					return fmt.Errorf("could not clear event cache: %w\n", err)
+				}
+				fmt.Println("Cleared the cached event list")
+				return nil
+			}
+
+			scope, _ := cmd.Flags().GetString("scope")
+			if err := validScope(scope); err != nil {
+				return err
+			}
+			events := catalog.Events
+			if refresh {
+				var err error
+				events, err = refreshEvents(true)
+				if err != nil {
+					return fmt.Errorf("could not get events: %w\n", err)
+				}
+			}
+			events = eventsForScope(events, scope)
+			ghesVersion, _ := cmd.Flags().GetString("ghes")
+			if ghesVersion != "" {
+				if _, ok := parseVersion(ghesVersion); !ok {
+					return fmt.Errorf("invalid GitHub Enterprise Server version %q, such as 3.8\n", ghesVersion)
+				}
+				events = eventsForServer(events, ghesVersion)
+			}
+			if len(args) == 0 {
+				return printEvents(term.FromEnv(), events)
+			}
+			for _, e := range events {
+				if e.Name == args[0] {
+					return printEvent(os.Stdout, e)
+				}
+			}
+			return fmt.Errorf("unknown event %q", args[0])
+		},
+	}
+	eventsCmd.Flags().Bool("refresh", false, "Download the list of events from https://octokit.github.io/webhooks and update the cached copy used by --refresh-events.")
+	eventsCmd.Flags().Bool("clear-cache", false, "Remove the cached copy of the downloaded event list.")
+	eventsCmd.Flags().String("scope", scopeRepository, "Only show events available for this kind of webhook: repository, organization, app or enterprise.")
+	eventsCmd.Flags().String("ghes", "", "Only show events sent by this GitHub Enterprise Server version, such as 3.8.")
+	eventsCmd.MarkFlagsMutuallyExclusive("refresh", "clear-cache")
+	eventsCmd.AddCommand(NewCmdEventsAdd())
+	eventsCmd.AddCommand(NewCmdEventsRemove())
+	eventsCmd.AddCommand(NewCmdEventsEdit())
+	return eventsCmd
+}
+
+func printEvents(t term.Term, events []Event) error {
+	width, _, err := t.Size()
+	if err != nil {
+		width = 80
+	}
+	tp := tableprinter.New(t.Out(), t.IsTerminalOutput(), width)
+	for _, e := range events {
+		tp.AddField(e.Name)
+		tp.AddField(e.Description)
+		tp.EndRow()
+	}
+	return tp.Render()
+}
+
+func printEvent(w io.Writer, e Event) error {
+	fmt.Fprintf(w, "%s\n%s\n\n", e.Name, e.Description)
+	if len(e.Actions) > 0 {
+		fmt.Fprintf(w, "Actions: %s\n", strings.Join(e.Actions, ", "))
+	}
+	if len(e.Availability) > 0 {
+		fmt.Fprintf(w, "Available for: %s\n", strings.Join(e.Availability, ", "))
+	}
+	if e.GHES == notOnGHES {
+		fmt.Fprintln(w, "Not available on GitHub Enterprise Server")
+	} else if e.GHES != "" {
+		fmt.Fprintf(w, "GitHub Enterprise Server: %s and later\n", e.GHES)
+	}
+	for _, example := range e.Examples {
+		fmt.Fprintln(w, "\nExample payload:")
+		if err := jsonpretty.Format(w, bytes.NewReader(example), "  ", false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// allEvents subscribes a webhook to every event, including ones added by
+// GitHub in the future.
+const allEvents = "*"
+
+// groupPrefix distinguishes event groups from events of the same name, such as
+// the issues event and the @issues group. Groups whose name is not an event,
+// such as ci, can be given without it.
+const groupPrefix = "@"
+
+// eventGroups are named sets of event patterns that are commonly subscribed to
+// together.
+var eventGroups = map[string][]string{
+	"ci":       {"check_run", "check_suite", "status", "workflow_*"},
+	"issues":   {"issues", "issue_comment", "label", "milestone"},
+	"pr":       {"pull_request*"},
+	"security": {"code_scanning_alert", "dependabot_alert", "repository_vulnerability_alert", "secret_scanning_*", "security_and_analysis"},
+}
+
+// eventGroup returns the members of the group named by pattern, which is
+// prefixed with groupPrefix or does not clash with the name of an event.
+func eventGroup(pattern string, available []string) ([]string, bool) {
+	name := strings.TrimPrefix(pattern, groupPrefix)
+	if name == pattern {
+		if _, isEvent := catalogEvent(name); isEvent || contains(available, name) {
+			return nil, false
+		}
+	}
+	members, ok := eventGroups[name]
+	return members, ok
+}
+
+func eventGroupNames() []string {
+	var names []string
+	for name := range eventGroups {
+		names = append(names, groupPrefix+name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// expandEvents resolves groups and glob patterns against the available events.
+// Plain names must be one of the available events or a group, and selecting
+// "*" subscribes to every event.
+func expandEvents(patterns []string, available []string) ([]string, error) {
+	var expanded []string
+	seen := map[string]bool{}
+	add := func(event string) {
+		if !seen[event] {
+			seen[event] = true
+			expanded = append(expanded, event)
+		}
+	}
+	addGroup := func(members []string) error {
+		for _, member := range members {
+			matches, err := matchEvents(member, available)
+			if err != nil {
+				return err
+			}
+			for _, event := range matches {
+				add(event)
+			}
+		}
+		return nil
+	}
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		switch {
+		case pattern == "":
+			continue
+		case pattern == allEvents:
+			return []string{allEvents}, nil
+		case strings.HasPrefix(pattern, groupPrefix):
+			members, ok := eventGroup(pattern, available)
+			if !ok {
+				return nil, fmt.Errorf("unknown event group %q, available groups: %s", pattern, strings.Join(eventGroupNames(), ", "))
+			}
+			if err := addGroup(members); err != nil {
+				return nil, err
+			}
+		case strings.ContainsAny(pattern, "*?["):
+			matches, err := matchEvents(pattern, available)
+			if err != nil {
+				return nil, err
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("pattern %q does not match any event", pattern)
+			}
+			for _, event := range matches {
+				add(event)
+			}
+		case contains(available, pattern):
+			add(pattern)
+		default:
+			if members, ok := eventGroup(pattern, available); ok {
+				if err := addGroup(members); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if _, ok := catalogEvent(pattern); ok {
+				return nil, fmt.Errorf("event %q is not available for this kind of webhook", pattern)
+			}
+			return nil, fmt.Errorf("unknown event %q", pattern)
+		}
+	}
+	return expanded, nil
+}
+
+func matchEvents(pattern string, available []string) ([]string, error) {
+	var matches []string
+	for _, event := range available {
+		ok, err := path.Match(pattern, event)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event pattern %q: %w", pattern, err)
+		}
+		if ok {
+			matches = append(matches, event)
+		}
+	}
+	return matches, nil
+}
+
+// eventChoices returns the options for choosing events interactively, with
+// "*" and the event groups offered alongside the individual events, and the
+// description of each option.
+func eventChoices(events []Event) ([]string, map[string]string) {
+	choices := append([]string{allEvents}, eventGroupNames()...)
+	choices = append(choices, eventNames(events)...)
+	descriptions := eventDescriptions(events)
+	descriptions[allEvents] = "Every event, including ones added in the future."
+	for name, members := range eventGroups {
+		descriptions[groupPrefix+name] = strings.Join(members, ", ")
+	}
+	return choices, descriptions
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
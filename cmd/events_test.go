@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_expandEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "plain events",
+			patterns: []string{"push", "release"},
+			want:     []string{"push", "release"},
+		},
+		{
+			name:     "wildcard replaces everything else",
+			patterns: []string{"push", "*"},
+			want:     []string{"*"},
+		},
+		{
+			name:     "glob pattern",
+			patterns: []string{"pull_request*"},
+			want:     []string{"pull_request", "pull_request_review", "pull_request_review_comment", "pull_request_review_thread"},
+		},
+		{
+			name:     "group",
+			patterns: []string{"@ci"},
+			want:     []string{"check_run", "check_suite", "status", "workflow_job", "workflow_run"},
+		},
+		{
+			name:     "group without prefix",
+			patterns: []string{"ci", "pr"},
+			want:     []string{"check_run", "check_suite", "status", "workflow_job", "workflow_run", "pull_request", "pull_request_review", "pull_request_review_comment", "pull_request_review_thread"},
+		},
+		{
+			name:     "event takes precedence over group of the same name",
+			patterns: []string{"issues"},
+			want:     []string{"issues"},
+		},
+		{
+			name:     "duplicates are removed",
+			patterns: []string{"push", "@issues", "issues", " push "},
+			want:     []string{"push", "issues", "issue_comment", "label", "milestone"},
+		},
+		{
+			name:     "unknown event",
+			patterns: []string{"push", "not_an_event"},
+			wantErr:  true,
+		},
+		{
+			name:     "event not available for repositories",
+			patterns: []string{"installation"},
+			wantErr:  true,
+		},
+		{
+			name:     "unknown group",
+			patterns: []string{"@unknown"},
+			wantErr:  true,
+		},
+		{
+			name:     "pattern without matches",
+			patterns: []string{"unknown_*"},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid pattern",
+			patterns: []string{"push["},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandEvents(tt.patterns, knownEvents)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expandEvents(%v) error = %v, wantErr %v", tt.patterns, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_printEvent(t *testing.T) {
+	event := Event{
+		Name:         "star",
+		Description:  "Activity related to a repository being starred.",
+		Actions:      []string{"created", "deleted"},
+		Availability: []string{"repository"},
+		Examples:     []json.RawMessage{json.RawMessage(`{"action":"created"}`)},
+	}
+	var out bytes.Buffer
+	if err := printEvent(&out, event); err != nil {
+		t.Fatalf("printEvent() error = %v", err)
+	}
+	assert.Equal(t, `star
+Activity related to a repository being starred.
+
+Actions: created, deleted
+Available for: repository
+
+Example payload:
+{
+  "action": "created"
+}
+`, out.String())
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// hookFilter selects hooks by their settings and last response. The zero
+// value matches every hook.
+type hookFilter struct {
+	active      bool
+	inactive    bool
+	events      []string
+	urlContains string
+	failing     bool
+}
+
+// addFilterFlags adds the flags read by filterFromFlags, describing what the
+// selected hooks are used for, such as "list" or "delete".
+func addFilterFlags(cmd *cobra.Command, action string) {
+	cmd.Flags().Bool("active", false, fmt.Sprintf("Only %s active webhooks.", action))
+	cmd.Flags().Bool("inactive", false, fmt.Sprintf("Only %s inactive webhooks.", action))
+	cmd.Flags().StringSlice("event", nil, fmt.Sprintf("Only %s webhooks receiving any of these events.", action))
+	cmd.Flags().String("url-contains", "", fmt.Sprintf("Only %s webhooks whose URL contains this text.", action))
+	cmd.Flags().Bool("failing", false, fmt.Sprintf("Only %s webhooks whose last delivery failed.", action))
+	cmd.MarkFlagsMutuallyExclusive("active", "inactive")
+}
+
+func filterFromFlags(cmd *cobra.Command) hookFilter {
+	var f hookFilter
+	f.active, _ = cmd.Flags().GetBool("active")
+	f.inactive, _ = cmd.Flags().GetBool("inactive")
+	f.events, _ = cmd.Flags().GetStringSlice("event")
+	f.urlContains, _ = cmd.Flags().GetString("url-contains")
+	f.failing, _ = cmd.Flags().GetBool("failing")
+	return f
+}
+
+// isSet reports whether the filter excludes any hooks.
+func (f hookFilter) isSet() bool {
+	return f.active || f.inactive || len(f.events) > 0 || f.urlContains != "" || f.failing
+}
+
+func (f hookFilter) match(hook Hook) bool {
+	if f.active && !hook.Active || f.inactive && hook.Active {
+		return false
+	}
+	if f.urlContains != "" && !strings.Contains(strings.ToLower(hook.Config.Url), strings.ToLower(f.urlContains)) {
+		return false
+	}
+	if f.failing && !hook.failing() {
+		return false
+	}
+	if len(f.events) > 0 && len(sharedEvents(hook.Events, f.events)) == 0 {
+		return false
+	}
+	return true
+}
+
+func filterHooks(hooks []Hook, f hookFilter) []Hook {
+	var matched []Hook
+	for _, hook := range hooks {
+		if f.match(hook) {
+			matched = append(matched, hook)
+		}
+	}
+	return matched
+}
+
+// failing reports whether the last delivery to the hook failed. Hooks that
+// never delivered anything are not failing.
+func (h Hook) failing() bool {
+	r := h.LastResponse
+	if r == nil {
+		return false
+	}
+	if r.Code != 0 {
+		return r.Code >= 300
+	}
+	return r.Status != "" && r.Status != "active" && r.Status != "unused"
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_filterHooks(t *testing.T) {
+	hooks := []Hook{
+		{Id: 1, Active: true, Events: []string{"push"}, Config: HookConfig{Url: "https://jenkins.example.com/hook"}, LastResponse: &HookResponse{Code: 200, Status: "active"}},
+		{Id: 2, Active: false, Events: []string{"release"}, Config: HookConfig{Url: "https://chat.example.com"}, LastResponse: &HookResponse{Code: 502, Status: "active"}},
+		{Id: 3, Active: true, Events: []string{"*"}, Config: HookConfig{Url: "https://Jenkins.example.org"}, LastResponse: &HookResponse{Status: "timed out"}},
+		{Id: 4, Active: true, Events: []string{"issues"}, Config: HookConfig{Url: "https://example.net"}, LastResponse: &HookResponse{Status: "unused"}},
+	}
+	tests := []struct {
+		name    string
+		filter  hookFilter
+		wantIds []int
+	}{
+		{name: "no filter", filter: hookFilter{}, wantIds: []int{1, 2, 3, 4}},
+		{name: "active", filter: hookFilter{active: true}, wantIds: []int{1, 3, 4}},
+		{name: "inactive", filter: hookFilter{inactive: true}, wantIds: []int{2}},
+		{name: "event", filter: hookFilter{events: []string{"push"}}, wantIds: []int{1, 3}},
+		{name: "url contains, ignoring case", filter: hookFilter{urlContains: "jenkins"}, wantIds: []int{1, 3}},
+		{name: "failing", filter: hookFilter{failing: true}, wantIds: []int{2, 3}},
+		{name: "combined", filter: hookFilter{active: true, failing: true, urlContains: "jenkins"}, wantIds: []int{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []int
+			for _, hook := range filterHooks(hooks, tt.filter) {
+				ids = append(ids, hook.Id)
+			}
+			assert.Equal(t, tt.wantIds, ids)
+			assert.Equal(t, tt.name != "no filter", tt.filter.isSet())
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh"
+	"github.com/cli/go-gh/pkg/api"
+)
+
+// notOnGHES marks catalog events that are only sent by GitHub.com.
+const notOnGHES = "none"
+
+// serverFeature is an API that GitHub Enterprise Server only offers from a
+// given version on.
+type serverFeature struct {
+	name  string
+	since string
+}
+
+var featureDeliveries = serverFeature{name: "webhook deliveries", since: "3.2"}
+
+var (
+	serverVersionsMu sync.Mutex
+	// serverVersions caches the version of each host, so that it is only
+	// looked up once per run.
+	serverVersions = map[string]string{}
+)
+
+// isEnterpriseHost reports whether host is a GitHub Enterprise Server, rather
+// than GitHub.com or a GitHub Enterprise Cloud tenant that follows it.
+func isEnterpriseHost(host string) bool {
+	host = strings.ToLower(host)
+	return host != "github.com" && host != "api.github.com" && !strings.HasSuffix(host, ".ghe.com")
+}
+
+// serverVersion returns the GitHub Enterprise Server version running on host,
+// or an empty string for GitHub.com.
+func serverVersion(host string) (string, error) {
+	if !isEnterpriseHost(host) {
+		return "", nil
+	}
+	serverVersionsMu.Lock()
+	defer serverVersionsMu.Unlock()
+	if version, ok := serverVersions[host]; ok {
+		return version, nil
+	}
+	client, err := gh.RESTClient(&api.ClientOptions{Host: host})
+	if err != nil {
+		return "", err
+	}
+	var meta struct {
+		InstalledVersion string `json:"installed_version"`
+	}
+	if err := client.Get("meta", &meta); err != nil {
+		return "", fmt.Errorf("could not get the version of %s: %w", host, err)
+	}
+	serverVersions[host] = meta.InstalledVersion
+	return meta.InstalledVersion, nil
+}
+
+// versionAtLeast compares the major and minor parts of two versions, such as
+// "3.7.2" and "3.8". Versions that cannot be parsed are assumed to be recent.
+func versionAtLeast(version, min string) bool {
+	v, ok := parseVersion(version)
+	if !ok {
+		return true
+	}
+	m, ok := parseVersion(min)
+	if !ok {
+		return true
+	}
+	if v[0] != m[0] {
+		return v[0] > m[0]
+	}
+	return v[1] >= m[1]
+}
+
+func parseVersion(version string) ([2]int, bool) {
+	var parsed [2]int
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return parsed, false
+	}
+	for i := range parsed {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return parsed, false
+		}
+		parsed[i] = n
+	}
+	return parsed, true
+}
+
+// checkFeature returns an error when host runs a GitHub Enterprise Server
+// version that does not offer feature. When the version cannot be determined,
+// the feature is assumed to be available.
+func checkFeature(host string, feature serverFeature) error {
+	version, err := serverVersion(host)
+	if err != nil || version == "" || versionAtLeast(version, feature.since) {
+		return nil
+	}
+	return fmt.Errorf("%s are not supported on %s, which runs GitHub Enterprise Server %s; they need version %s or later\n", feature.name, host, version, feature.since)
+}
+
+// explainUnsupported replaces the "Not Found" error of an API that host might
+// not offer with a clearer one, when host runs a version without feature.
+func explainUnsupported(host string, feature serverFeature, err error) error {
+	if !isNotFound(err) {
+		return err
+	}
+	if unsupported := checkFeature(host, feature); unsupported != nil {
+		return unsupported
+	}
+	return err
+}
+
+// availableOnServer reports whether an event is sent by a GitHub Enterprise
+// Server of the given version. Every event is available on GitHub.com.
+func (e Event) availableOnServer(version string) bool {
+	switch {
+	case version == "" || e.GHES == "":
+		return true
+	case e.GHES == notOnGHES:
+		return false
+	default:
+		return versionAtLeast(version, e.GHES)
+	}
+}
+
+// eventsForServer filters events down to the ones sent by a GitHub Enterprise
+// Server of the given version.
+func eventsForServer(events []Event, version string) []Event {
+	var filtered []Event
+	for _, e := range events {
+		if e.availableOnServer(version) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
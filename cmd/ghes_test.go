@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func ghesTestConfig() string {
+	return `
+hosts:
+  github.com:
+    user: user1
+    oauth_token: abc123
+  ghe.example.com:
+    user: user1
+    oauth_token: def456
+`
+}
+
+func Test_versionAtLeast(t *testing.T) {
+	tests := []struct {
+		version string
+		min     string
+		want    bool
+	}{
+		{version: "3.8.0", min: "3.8", want: true},
+		{version: "3.10.1", min: "3.8", want: true},
+		{version: "3.7.12", min: "3.8", want: false},
+		{version: "2.22.0", min: "3.2", want: false},
+		{version: "4.0.0", min: "3.10", want: true},
+		{version: "unknown", min: "3.8", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version+">="+tt.min, func(t *testing.T) {
+			assert.Equal(t, tt.want, versionAtLeast(tt.version, tt.min))
+		})
+	}
+}
+
+func Test_isEnterpriseHost(t *testing.T) {
+	assert.False(t, isEnterpriseHost("github.com"))
+	assert.False(t, isEnterpriseHost("GitHub.com"))
+	assert.False(t, isEnterpriseHost("octocorp.ghe.com"))
+	assert.True(t, isEnterpriseHost("ghe.example.com"))
+}
+
+func Test_eventsForServer(t *testing.T) {
+	events := []Event{
+		{Name: "push"},
+		{Name: "workflow_job", GHES: "3.3"},
+		{Name: "dependabot_alert", GHES: "3.8"},
+		{Name: "sponsorship", GHES: notOnGHES},
+	}
+	tests := []struct {
+		name    string
+		version string
+		want    []string
+	}{
+		{
+			name:    "GitHub.com",
+			version: "",
+			want:    []string{"push", "workflow_job", "dependabot_alert", "sponsorship"},
+		},
+		{
+			name:    "recent server",
+			version: "3.9.1",
+			want:    []string{"push", "workflow_job", "dependabot_alert"},
+		},
+		{
+			name:    "older server",
+			version: "3.4.0",
+			want:    []string{"push", "workflow_job"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, eventNames(eventsForServer(events, tt.version)))
+		})
+	}
+}
+
+func Test_serverVersion(t *testing.T) {
+	stubConfig(t, ghesTestConfig())
+	t.Cleanup(gock.Off)
+	t.Cleanup(func() { serverVersions = map[string]string{} })
+	gock.New("https://ghe.example.com").
+		Get("api/v3/meta").
+		Reply(200).
+		JSON(`{"verifiable_password_authentication": true, "installed_version": "3.7.2"}`)
+
+	version, err := serverVersion("github.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "", version)
+
+	version, err = serverVersion("ghe.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "3.7.2", version)
+	// The version is cached, so the server is only asked once.
+	version, err = serverVersion("ghe.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "3.7.2", version)
+	assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+}
+
+func Test_getDeliveriesUnsupported(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		wantErr string
+	}{
+		{
+			name:    "server without the deliveries API",
+			version: "3.1.0",
+			wantErr: "webhook deliveries are not supported on ghe.example.com, which runs GitHub Enterprise Server 3.1.0; they need version 3.2 or later\n",
+		},
+		{
+			name:    "server with the deliveries API",
+			version: "3.8.0",
+			wantErr: "HTTP 404: 404 Not Found (https://ghe.example.com/api/v3/repos/octocat/Hello-World/hooks/1/deliveries?per_page=10)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubConfig(t, ghesTestConfig())
+			t.Cleanup(gock.Off)
+			t.Cleanup(func() { serverVersions = map[string]string{} })
+			gock.New("https://ghe.example.com").
+				Get("api/v3/repos/octocat/Hello-World/hooks/1/deliveries").
+				Reply(404)
+			gock.New("https://ghe.example.com").
+				Get("api/v3/meta").
+				Reply(200).
+				JSON(`{"installed_version": "` + tt.version + `"}`)
+
+			repo := MockRepo{host: "ghe.example.com", name: "Hello-World", owner: "octocat"}
+			_, err := getDeliveries(repo, "1", 10)
+			assert.EqualError(t, err, tt.wantErr)
+			assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+		})
+	}
+}
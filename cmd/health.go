@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/cli/go-gh/pkg/repository"
+	"github.com/cli/go-gh/pkg/tableprinter"
+	"github.com/cli/go-gh/pkg/term"
+	"github.com/spf13/cobra"
+)
+
+// hookHealth summarizes the recent deliveries of a hook.
+type hookHealth struct {
+	Repo        string
+	Hook        Hook
+	Deliveries  int
+	Failures    int
+	LastSuccess string
+	AvgDuration float64
+	CommonError string
+}
+
+// failureRate is the percentage of recent deliveries that failed.
+func (h hookHealth) failureRate() float64 {
+	if h.Deliveries == 0 {
+		return 0
+	}
+	return float64(h.Failures) / float64(h.Deliveries) * 100
+}
+
+func NewCmdHealth() *cobra.Command {
+	var healthCmd = &cobra.Command{
+		Use:          "health",
+		Short:        "Report the webhooks with failing deliveries, worst first.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			threshold, _ := cmd.Flags().GetFloat64("threshold")
+			deliveries, _ := cmd.Flags().GetInt("deliveries")
+			if deliveries < 1 || deliveries > 100 {
+				return fmt.Errorf("--deliveries must be between 1 and 100\n")
+			}
+
+			repos, err := getRepos(cmd)
+			if err != nil {
+				return err
+			}
+			if len(repos) > 0 {
+				if err := checkFeature(repos[0].Host(), featureDeliveries); err != nil {
+					fmt.Fprintf(os.Stderr, "%vUsing the last response of each webhook instead\n", err)
+					deliveries = 0
+				}
+			}
+			var report []hookHealth
+			for _, repo := range repos {
+				health, err := repoHealth(repo, deliveries)
+				if err != nil {
+					return err
+				}
+				report = append(report, health...)
+			}
+			if len(report) == 0 {
+				fmt.Println("No webhooks found")
+				return nil
+			}
+			rankHealth(report)
+			if err := printHealth(term.FromEnv(), report); err != nil {
+				return err
+			}
+
+			failing := 0
+			for _, h := range report {
+				if h.failureRate() > threshold {
+					failing++
+				}
+			}
+			if failing > 0 {
+				return fmt.Errorf("%d webhooks failed more than %g%% of their recent deliveries\n", failing, threshold)
+			}
+			return nil
+		},
+	}
+	healthCmd.Flags().String("owner", "", "Report on the webhooks of every repository of this organization or user.")
+	healthCmd.Flags().Float64("threshold", 50, "Exit with an error when a webhook failed more than this percentage of its recent deliveries.")
+	healthCmd.Flags().Int("deliveries", 50, "The number of recent deliveries to look at for each webhook, at most 100.")
+	return healthCmd
+}
+
+// repoHealth returns the health of every hook in a repository, looking at up
+// to perPage deliveries of each hook, or only at its last response when
+// perPage is 0. Repositories whose hooks cannot be read, such as when the user
+// is not an admin, are skipped.
+func repoHealth(repo repository.Repository, perPage int) ([]hookHealth, error) {
+	name := repo.Owner() + "/" + repo.Name()
+	hooks, err := getWebhooks(repo)
+	if isNotFound(err) {
+		fmt.Fprintf(os.Stderr, "Skipping %s, its webhooks could not be read\n", name)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get webhooks of %s: %w\n", name, err)
+	}
+	var report []hookHealth
+	for _, hook := range hooks {
+		var deliveries []Delivery
+		if perPage > 0 {
+			deliveries, err = getDeliveries(repo, strconv.Itoa(hook.Id), perPage)
+			if err != nil {
+				return nil, fmt.Errorf("could not get deliveries of hook %d in %s: %w\n", hook.Id, name, err)
+			}
+		}
+		health := computeHealth(hook, deliveries)
+		health.Repo = name
+		report = append(report, health)
+	}
+	return report, nil
+}
+
+// computeHealth summarizes deliveries, newest first as returned by the API.
+// Without recent deliveries, the last response of the hook is used instead.
+func computeHealth(hook Hook, deliveries []Delivery) hookHealth {
+	health := hookHealth{Hook: hook, Deliveries: len(deliveries)}
+	if len(deliveries) == 0 {
+		if hook.failing() {
+			health.Deliveries = 1
+			health.Failures = 1
+			health.CommonError = hook.LastResponse.Message
+		}
+		return health
+	}
+
+	var total float64
+	errorCounts := map[string]int{}
+	for _, d := range deliveries {
+		total += d.Duration
+		if d.succeeded() {
+			if health.LastSuccess == "" {
+				health.LastSuccess = d.DeliveredAt
+			}
+			continue
+		}
+		health.Failures++
+		errorCounts[d.Status]++
+		// Ties go to the most recent error.
+		if errorCounts[d.Status] > errorCounts[health.CommonError] {
+			health.CommonError = d.Status
+		}
+	}
+	health.AvgDuration = total / float64(len(deliveries))
+	return health
+}
+
+// rankHealth sorts hooks worst first: by failure rate, then by number of
+// failures, then by the oldest last success.
+func rankHealth(report []hookHealth) {
+	sort.SliceStable(report, func(i, j int) bool {
+		a, b := report[i], report[j]
+		if a.failureRate() != b.failureRate() {
+			return a.failureRate() > b.failureRate()
+		}
+		if a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		return a.LastSuccess < b.LastSuccess
+	})
+}
+
+func printHealth(t term.Term, report []hookHealth) error {
+	width, _, err := t.Size()
+	if err != nil {
+		width = 80
+	}
+	tp := tableprinter.New(t.Out(), t.IsTerminalOutput(), width)
+	if t.IsTerminalOutput() {
+		for _, header := range []string{"REPO", "ID", "URL", "FAILURES", "LAST SUCCESS", "AVG DURATION", "MOST COMMON ERROR"} {
+			tp.AddField(header)
+		}
+		tp.EndRow()
+	}
+	for _, h := range report {
+		lastSuccess := h.LastSuccess
+		if lastSuccess == "" {
+			lastSuccess = "-"
+		}
+		tp.AddField(h.Repo)
+		tp.AddField(strconv.Itoa(h.Hook.Id))
+		tp.AddField(h.Hook.Config.Url)
+		tp.AddField(fmt.Sprintf("%.0f%% (%d/%d)", h.failureRate(), h.Failures, h.Deliveries))
+		tp.AddField(lastSuccess)
+		tp.AddField(fmt.Sprintf("%.2fs", h.AvgDuration))
+		tp.AddField(h.CommonError)
+		tp.EndRow()
+	}
+	return tp.Render()
+}
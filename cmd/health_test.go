@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_computeHealth(t *testing.T) {
+	tests := []struct {
+		name       string
+		hook       Hook
+		deliveries []Delivery
+		want       hookHealth
+	}{
+		{
+			name: "mixed deliveries",
+			hook: Hook{Id: 1},
+			deliveries: []Delivery{
+				{DeliveredAt: "2023-03-03T00:00:00Z", StatusCode: 502, Status: "Invalid HTTP Response: 502", Duration: 1},
+				{DeliveredAt: "2023-03-02T00:00:00Z", StatusCode: 200, Status: "OK", Duration: 0.5},
+				{DeliveredAt: "2023-03-01T00:00:00Z", StatusCode: 0, Status: "timed out", Duration: 10},
+				{DeliveredAt: "2023-02-28T00:00:00Z", StatusCode: 0, Status: "timed out", Duration: 10},
+				{DeliveredAt: "2023-02-27T00:00:00Z", StatusCode: 200, Status: "OK", Duration: 0.5},
+			},
+			want: hookHealth{
+				Hook:        Hook{Id: 1},
+				Deliveries:  5,
+				Failures:    3,
+				LastSuccess: "2023-03-02T00:00:00Z",
+				AvgDuration: 4.4,
+				CommonError: "timed out",
+			},
+		},
+		{
+			name: "no deliveries and a failed last response",
+			hook: Hook{Id: 2, LastResponse: &HookResponse{Code: 404, Status: "misconfigured", Message: "Not Found"}},
+			want: hookHealth{
+				Hook:        Hook{Id: 2, LastResponse: &HookResponse{Code: 404, Status: "misconfigured", Message: "Not Found"}},
+				Deliveries:  1,
+				Failures:    1,
+				CommonError: "Not Found",
+			},
+		},
+		{
+			name: "no deliveries",
+			hook: Hook{Id: 3, LastResponse: &HookResponse{Status: "unused"}},
+			want: hookHealth{Hook: Hook{Id: 3, LastResponse: &HookResponse{Status: "unused"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := computeHealth(tt.hook, tt.deliveries)
+			assert.InDelta(t, tt.want.AvgDuration, got.AvgDuration, 0.001)
+			got.AvgDuration = tt.want.AvgDuration
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_rankHealth(t *testing.T) {
+	report := []hookHealth{
+		{Hook: Hook{Id: 1}, Deliveries: 10, Failures: 1},
+		{Hook: Hook{Id: 2}, Deliveries: 10, Failures: 5},
+		{Hook: Hook{Id: 3}, Deliveries: 2, Failures: 1},
+		{Hook: Hook{Id: 4}},
+	}
+	rankHealth(report)
+	var ids []int
+	for _, h := range report {
+		ids = append(ids, h.Hook.Id)
+	}
+	assert.Equal(t, []int{2, 3, 1, 4}, ids)
+}
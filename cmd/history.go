@@ -0,0 +1,422 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/cli/go-gh/pkg/config"
+	"github.com/cli/go-gh/pkg/repository"
+	"github.com/cli/go-gh/pkg/tableprinter"
+	"github.com/cli/go-gh/pkg/term"
+	"github.com/lucasmelin/gh-hook/tui"
+	"github.com/spf13/cobra"
+)
+
+// historyEntry records a change made to a webhook, with the hook as it was
+// before and after the change. Secrets are redacted.
+type historyEntry struct {
+	Time   time.Time `json:"time"`
+	User   string    `json:"user,omitempty"`
+	Host   string    `json:"host"`
+	Scope  string    `json:"scope"`
+	Target string    `json:"target"`
+	Action string    `json:"action"`
+	HookId int       `json:"hook_id,omitempty"`
+	Before *Hook     `json:"before,omitempty"`
+	After  *Hook     `json:"after,omitempty"`
+	// Undoes is the number of the change that a restore undid.
+	Undoes int `json:"undoes,omitempty"`
+}
+
+// url returns the URL of the changed hook.
+func (e historyEntry) url() string {
+	if e.After != nil {
+		return e.After.Config.Url
+	}
+	if e.Before != nil {
+		return e.Before.Config.Url
+	}
+	return ""
+}
+
+// historyPath is the log of changes, in the gh state directory.
+func historyPath() string {
+	return filepath.Join(config.StateDir(), "gh-hook", "history.jsonl")
+}
+
+// recordHistory appends a change to the history.
+func recordHistory(target hookTarget, action string, before, after *Hook) {
+	saveHistory(newHistoryEntry(target, action, before, after))
+}
+
+// newHistoryEntry describes a change to a hook of target.
+func newHistoryEntry(target hookTarget, action string, before, after *Hook) historyEntry {
+	entry := historyEntry{
+		Time:   time.Now().UTC(),
+		User:   currentUser(target.Host()),
+		Host:   target.Host(),
+		Scope:  targetKind(target),
+		Target: historyTarget(target),
+		Action: action,
+		Before: redactHook(before),
+		After:  redactHook(after),
+	}
+	if after != nil {
+		entry.HookId = after.Id
+	} else if before != nil {
+		entry.HookId = before.Id
+	}
+	return entry
+}
+
+// saveHistory appends entry to the history. Failing to record a change does
+// not undo it, so errors are only reported.
+func saveHistory(entry historyEntry) {
+	if err := appendHistory(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "could not record the change in the history: %v\n", err)
+	}
+}
+
+func appendHistory(entry historyEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := openHistory()
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// openHistory opens the history for appending, creating it if needed.
+func openHistory() (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(historyPath()), 0755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(historyPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+}
+
+// checkHistory makes sure that a change can be recorded before it is made,
+// for changes that cannot be undone without their record.
+func checkHistory() error {
+	f, err := openHistory()
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// readHistory returns every recorded change, oldest first.
+func readHistory() ([]historyEntry, error) {
+	f, err := os.Open(historyPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entries []historyEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var entry historyEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("could not parse line %d of %s: %w", line, historyPath(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// redactHook returns a copy of hook without its secret, or nil for no hook.
+func redactHook(hook *Hook) *Hook {
+	if hook == nil {
+		return nil
+	}
+	redacted := *hook
+	if redacted.Config.Secret != "" {
+		redacted.Config.Secret = redactedSecret
+	}
+	redacted.Config.SecretFile = ""
+	return &redacted
+}
+
+// currentUser is the user gh is logged in as on host.
+func currentUser(host string) string {
+	cfg, err := config.Read()
+	if err != nil {
+		return ""
+	}
+	user, _ := cfg.Get([]string{"hosts", host, "user"})
+	return user
+}
+
+// historyTarget identifies a target in the history, so that it can be found
+// again to undo a change.
+func historyTarget(t hookTarget) string {
+	if app, ok := t.(appTarget); ok {
+		return app.appId
+	}
+	return targetName(t)
+}
+
+// targetFromHistory returns the target of a recorded change. App webhooks
+// cannot be found again, as the history does not record the key of the app.
+func targetFromHistory(entry historyEntry) (hookTarget, error) {
+	switch entry.Scope {
+	case scopeRepository:
+		return repository.ParseWithHost(entry.Target, entry.Host)
+	case scopeOrganization:
+		return orgTarget{host: entry.Host, org: entry.Target}, nil
+	case scopeEnterprise:
+		return enterpriseTarget{host: entry.Host}, nil
+	default:
+		return nil, fmt.Errorf("changes to %s webhooks cannot be undone\n", entry.Scope)
+	}
+}
+
+// historyFilter selects the entries shown by the history command.
+type historyFilter struct {
+	action string
+	target string
+	hookId int
+}
+
+func (f historyFilter) match(entry historyEntry) bool {
+	return (f.action == "" || entry.Action == f.action) &&
+		(f.target == "" || entry.Target == f.target) &&
+		(f.hookId == 0 || entry.HookId == f.hookId)
+}
+
+func NewCmdHistory() *cobra.Command {
+	var historyCmd = &cobra.Command{
+		Use:          "history",
+		Short:        "Show the changes made to webhooks with gh hook, newest first.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("format")
+			limit, _ := cmd.Flags().GetInt("limit")
+			if format != formatText && format != formatJSON {
+				return fmt.Errorf("unknown format %q, use text or json\n", format)
+			}
+			var filter historyFilter
+			filter.action, _ = cmd.Flags().GetString("action")
+			filter.target, _ = cmd.Flags().GetString("target")
+			filter.hookId, _ = cmd.Flags().GetInt("hook")
+
+			entries, err := readHistory()
+			if err != nil {
+				return fmt.Errorf("could not read history: %w\n", err)
+			}
+			t := term.FromEnv()
+			if format == formatJSON {
+				return writeHistoryJSON(t.Out(), entries, filter, limit)
+			}
+			if len(entries) == 0 {
+				fmt.Fprintln(t.Out(), "No changes have been recorded")
+				return nil
+			}
+			width, _, err := t.Size()
+			if err != nil {
+				width = 80
+			}
+			return printHistory(t.Out(), t.IsTerminalOutput(), width, entries, filter, limit)
+		},
+	}
+	historyCmd.Flags().String("action", "", "Only show changes of this kind, such as create, update or delete.")
+	historyCmd.Flags().String("target", "", "Only show changes to this repository, such as octocat/hello-world.")
+	historyCmd.Flags().Int("hook", 0, "Only show changes to the webhook with this ID.")
+	historyCmd.Flags().IntP("limit", "L", 30, "The number of changes to show, or 0 for all of them.")
+	historyCmd.Flags().String("format", formatText, "Output format: text or json.")
+	historyCmd.AddCommand(NewCmdHistoryUndo())
+	return historyCmd
+}
+
+func NewCmdHistoryUndo() *cobra.Command {
+	var undoCmd = &cobra.Command{
+		Use:          "undo <change>",
+		Short:        "Recreate a webhook removed by a recorded delete.",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			yes, _ := cmd.Flags().GetBool("yes")
+			force, _ := cmd.Flags().GetBool("force")
+			policyFile, _ := cmd.Flags().GetString("policy")
+			noSecret, _ := cmd.Flags().GetBool("no-secret")
+
+			entries, err := readHistory()
+			if err != nil {
+				return fmt.Errorf("could not read history: %w\n", err)
+			}
+			n, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("no change %s in the history\n", args[0])
+			}
+			entry, err := undoableChange(entries, n)
+			if err != nil {
+				return err
+			}
+			target, err := targetFromHistory(entry)
+			if err != nil {
+				return err
+			}
+			if err := enforcePolicy(policyFile, force, recreatedHook(*entry.Before, noSecret)); err != nil {
+				return err
+			}
+			confirmed, err := confirmHooks(term.FromEnv(), "recreate", []Hook{*entry.Before}, dryRun, yes)
+			if err != nil || !confirmed {
+				return err
+			}
+			created, err := recreateHook(target, *entry.Before, n, noSecret)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Recreated hook %d as %d ♻️\n", entry.HookId, created.Id)
+			return nil
+		},
+	}
+	undoCmd.Flags().Bool("dry-run", false, "Show the webhook that would be recreated without creating it.")
+	undoCmd.Flags().BoolP("yes", "y", false, "Recreate the webhook without asking for confirmation.")
+	undoCmd.Flags().Bool("force", false, "Recreate the webhook even if it violates the policy.")
+	undoCmd.Flags().Bool("no-secret", false, "Recreate a webhook that had a secret without one, instead of asking for it.")
+	undoCmd.Flags().String("policy", "", "Check the webhook against this policy file instead of gh-hook/policy.json in the gh config directory.")
+	return undoCmd
+}
+
+// undoableChange returns change n of the history, numbered from 1, when it is
+// a delete that has not been undone yet.
+func undoableChange(entries []historyEntry, n int) (historyEntry, error) {
+	if n < 1 || n > len(entries) {
+		return historyEntry{}, fmt.Errorf("no change %d in the history\n", n)
+	}
+	entry := entries[n-1]
+	if entry.Action != "delete" || entry.Before == nil {
+		return historyEntry{}, fmt.Errorf("change %d is a %s, only deletes can be undone\n", n, entry.Action)
+	}
+	for i, other := range entries {
+		if other.Undoes == n {
+			return historyEntry{}, fmt.Errorf("change %d was already undone by change %d\n", n, i+1)
+		}
+	}
+	return entry, nil
+}
+
+// recreatedHook is the hook that recreates recorded, whose secret is redacted.
+// With noSecret, it is recreated without a secret.
+func recreatedHook(recorded Hook, noSecret bool) Hook {
+	hook := Hook{
+		Name:   recorded.Name,
+		Active: recorded.Active,
+		Events: recorded.Events,
+		Config: recorded.Config,
+	}
+	if noSecret {
+		hook.Config.Secret = ""
+	}
+	return hook
+}
+
+// recreateHook creates a hook again from its recorded definition, and records
+// it as a restore undoing change undoes. The API never returns secrets, so a
+// hook that had one asks for a new one, unless noSecret is set. Callers check
+// the recorded hooks against the policy first, so that none of a batch is
+// created when one of them violates it.
+func recreateHook(target hookTarget, recorded Hook, undoes int, noSecret bool) (Hook, error) {
+	hook := recreatedHook(recorded, true)
+	if recorded.Config.Secret != "" {
+		if noSecret {
+			fmt.Fprintf(os.Stderr, "Recreating hook %d without its secret, so its deliveries will not be signed\n", recorded.Id)
+		} else {
+			if !term.FromEnv().IsTerminalOutput() {
+				return Hook{}, fmt.Errorf("hook %d had a secret, which cannot be recovered; run in a terminal to enter it, or pass --no-secret\n", recorded.Id)
+			}
+			secret, err := tui.Input(true, fmt.Sprintf("Secret for %s: ", recorded.Config.Url))
+			if err != nil {
+				return Hook{}, fmt.Errorf("could not read secret: %w\n", err)
+			}
+			if secret == "" {
+				return Hook{}, fmt.Errorf("hook %d had a secret, enter one or pass --no-secret to recreate it without one\n", recorded.Id)
+			}
+			hook.Config.Secret = secret
+		}
+	}
+	if err := checkTarget(target, "create"); err != nil {
+		return Hook{}, err
+	}
+	created, err := postHook(target, hook)
+	if err != nil {
+		return Hook{}, err
+	}
+	entry := newHistoryEntry(target, "restore", nil, &created)
+	entry.Undoes = undoes
+	saveHistory(entry)
+	return created, nil
+}
+
+// selectHistory returns the entries matching filter, newest first, along with
+// their number in the history, which undo takes.
+func selectHistory(entries []historyEntry, filter historyFilter, limit int) ([]historyEntry, []int) {
+	var selected []historyEntry
+	var numbers []int
+	for i := len(entries) - 1; i >= 0; i-- {
+		if limit > 0 && len(selected) == limit {
+			break
+		}
+		if filter.match(entries[i]) {
+			selected = append(selected, entries[i])
+			numbers = append(numbers, i+1)
+		}
+	}
+	return selected, numbers
+}
+
+func printHistory(w io.Writer, isTTY bool, width int, entries []historyEntry, filter historyFilter, limit int) error {
+	selected, numbers := selectHistory(entries, filter, limit)
+	tp := tableprinter.New(w, isTTY, width)
+	if isTTY {
+		for _, header := range []string{"CHANGE", "TIME", "USER", "ACTION", "TARGET", "HOOK", "URL"} {
+			tp.AddField(header)
+		}
+		tp.EndRow()
+	}
+	for i, entry := range selected {
+		tp.AddField(strconv.Itoa(numbers[i]))
+		tp.AddField(entry.Time.Local().Format(time.RFC3339))
+		tp.AddField(entry.User)
+		action := entry.Action
+		if entry.Undoes > 0 {
+			action = fmt.Sprintf("%s #%d", action, entry.Undoes)
+		}
+		tp.AddField(action)
+		tp.AddField(entry.Target)
+		tp.AddField(strconv.Itoa(entry.HookId))
+		tp.AddField(entry.url())
+		tp.EndRow()
+	}
+	return tp.Render()
+}
+
+// writeHistoryJSON writes the selected entries as JSON lines, like the log.
+func writeHistoryJSON(w io.Writer, entries []historyEntry, filter historyFilter, limit int) error {
+	selected, _ := selectHistory(entries, filter, limit)
+	encoder := json.NewEncoder(w)
+	for _, entry := range selected {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_recordHistory(t *testing.T) {
+	stubConfig(t, testConfig())
+	repo := MockRepo{host: "github.com", name: "test-repo", owner: "lucasmelin"}
+	before := Hook{Id: 1, Active: true, Config: HookConfig{Url: "https://example.com/webhook", Secret: "s3cret"}}
+	after := before
+	after.Active = false
+
+	recordHistory(repo, "disable", &before, &after)
+
+	entries, err := readHistory()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, "user1", entry.User)
+	assert.Equal(t, "github.com", entry.Host)
+	assert.Equal(t, scopeRepository, entry.Scope)
+	assert.Equal(t, "lucasmelin/test-repo", entry.Target)
+	assert.Equal(t, "disable", entry.Action)
+	assert.Equal(t, 1, entry.HookId)
+	assert.Equal(t, redactedSecret, entry.Before.Config.Secret)
+	assert.False(t, entry.After.Active)
+	// The recorded hooks are copies, so the secret of the caller is untouched.
+	assert.Equal(t, "s3cret", before.Config.Secret)
+}
+
+func Test_readHistoryMissing(t *testing.T) {
+	stubConfig(t, testConfig())
+	entries, err := readHistory()
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func Test_deleteHookRecordsHistory(t *testing.T) {
+	stubConfig(t, testConfig())
+	t.Cleanup(gock.Off)
+	gock.New("https://api.github.com").
+		Get("repos/lucasmelin/test-repo/hooks/1").
+		Reply(200).
+		JSON(`{"id": 1, "name": "web", "active": true, "events": ["push"], "config": {"url": "https://example.com/webhook", "secret": "********"}}`)
+	gock.New("https://api.github.com").
+		Delete("repos/lucasmelin/test-repo/hooks/1").
+		Reply(204)
+
+	repo := MockRepo{host: "github.com", name: "test-repo", owner: "lucasmelin"}
+	require.NoError(t, deleteHook(repo, "1"))
+
+	entries, err := readHistory()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "delete", entries[0].Action)
+	assert.Nil(t, entries[0].After)
+	assert.Equal(t, []string{"push"}, entries[0].Before.Events)
+	assert.Equal(t, "https://example.com/webhook", entries[0].url())
+	assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+}
+
+func Test_selectHistory(t *testing.T) {
+	entries := []historyEntry{
+		{Action: "create", Target: "octocat/one", HookId: 1},
+		{Action: "delete", Target: "octocat/one", HookId: 1},
+		{Action: "create", Target: "octocat/two", HookId: 2},
+		{Action: "update", Target: "octocat/two", HookId: 2},
+	}
+	tests := []struct {
+		name        string
+		filter      historyFilter
+		limit       int
+		wantActions []string
+		wantNumbers []int
+	}{
+		{
+			name:        "newest first",
+			wantActions: []string{"update", "create", "delete", "create"},
+			wantNumbers: []int{4, 3, 2, 1},
+		},
+		{
+			name:        "limit",
+			limit:       2,
+			wantActions: []string{"update", "create"},
+			wantNumbers: []int{4, 3},
+		},
+		{
+			name:        "by action",
+			filter:      historyFilter{action: "create"},
+			wantActions: []string{"create", "create"},
+			wantNumbers: []int{3, 1},
+		},
+		{
+			name:        "by target and hook",
+			filter:      historyFilter{target: "octocat/one", hookId: 1},
+			wantActions: []string{"delete", "create"},
+			wantNumbers: []int{2, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, numbers := selectHistory(entries, tt.filter, tt.limit)
+			var actions []string
+			for _, entry := range selected {
+				actions = append(actions, entry.Action)
+			}
+			assert.Equal(t, tt.wantActions, actions)
+			assert.Equal(t, tt.wantNumbers, numbers)
+		})
+	}
+}
+
+func Test_printHistory(t *testing.T) {
+	entries := []historyEntry{
+		{
+			Time:   time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC),
+			User:   "octocat",
+			Target: "octocat/hello-world",
+			Action: "delete",
+			HookId: 1,
+			Before: &Hook{Id: 1, Config: HookConfig{Url: "https://example.com/webhook"}},
+		},
+	}
+	var out bytes.Buffer
+	require.NoError(t, printHistory(&out, false, 80, entries, historyFilter{}, 0))
+	assert.Contains(t, out.String(), "1\t")
+	assert.Contains(t, out.String(), "\toctocat\tdelete\toctocat/hello-world\t1\thttps://example.com/webhook\n")
+}
+
+func Test_targetFromHistory(t *testing.T) {
+	target, err := targetFromHistory(historyEntry{Scope: scopeRepository, Host: "ghe.example.com", Target: "octocat/hello-world"})
+	require.NoError(t, err)
+	assert.Equal(t, "repos/octocat/hello-world/hooks", hooksPath(target))
+	assert.Equal(t, "ghe.example.com", target.Host())
+
+	target, err = targetFromHistory(historyEntry{Scope: scopeEnterprise, Host: "ghe.example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, enterpriseTarget{host: "ghe.example.com"}, target)
+
+	_, err = targetFromHistory(historyEntry{Scope: scopeApp, Host: "github.com", Target: "12345"})
+	assert.Error(t, err)
+}
+
+func Test_readHistoryInvalid(t *testing.T) {
+	stubConfig(t, testConfig())
+	require.NoError(t, appendHistory(historyEntry{Action: "create"}))
+	f, err := os.OpenFile(historyPath(), os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString("not json\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	_, err = readHistory()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func Test_undoableChange(t *testing.T) {
+	deleted := &Hook{Id: 1, Config: HookConfig{Url: "https://example.com/webhook"}}
+	entries := []historyEntry{
+		{Action: "create", HookId: 1, After: deleted},
+		{Action: "delete", HookId: 1, Before: deleted},
+		{Action: "restore", HookId: 2, Undoes: 2},
+		{Action: "delete", HookId: 3, Before: &Hook{Id: 3}},
+	}
+	tests := []struct {
+		name    string
+		n       int
+		wantErr string
+	}{
+		{name: "delete", n: 4},
+		{name: "not a delete", n: 1, wantErr: "only deletes can be undone"},
+		{name: "already undone", n: 2, wantErr: "already undone by change 3"},
+		{name: "out of range", n: 5, wantErr: "no change 5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := undoableChange(entries, tt.n)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, entries[tt.n-1], entry)
+		})
+	}
+}
+
+func Test_recreateHookRecordsRestore(t *testing.T) {
+	stubConfig(t, testConfig())
+	t.Cleanup(gock.Off)
+	gock.New("https://api.github.com").
+		Post("repos/lucasmelin/test-repo/hooks").
+		BodyString(`{"name":"web","active":true,"events":["push"],"config":{"url":"https://example.com/webhook"}}`).
+		Reply(201).
+		JSON(`{"id": 2, "name": "web", "active": true, "events": ["push"], "config": {"url": "https://example.com/webhook"}}`)
+
+	repo := MockRepo{host: "github.com", name: "test-repo", owner: "lucasmelin"}
+	recorded := Hook{Id: 1, Name: "web", Active: true, Events: []string{"push"}, Config: HookConfig{Url: "https://example.com/webhook"}}
+	created, err := recreateHook(repo, recorded, 7, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, created.Id)
+
+	entries, err := readHistory()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "restore", entries[0].Action)
+	assert.Equal(t, 7, entries[0].Undoes)
+	assert.Equal(t, 2, entries[0].HookId)
+	assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+}
+
+func Test_recreateHookSecret(t *testing.T) {
+	stubConfig(t, testConfig())
+	t.Cleanup(gock.Off)
+	repo := MockRepo{host: "github.com", name: "test-repo", owner: "lucasmelin"}
+	recorded := Hook{Id: 1, Name: "web", Active: true, Events: []string{"push"}, Config: HookConfig{Url: "https://example.com/webhook", Secret: redactedSecret}}
+
+	_, err := recreateHook(repo, recorded, 7, false)
+	if assert.Error(t, err, "the secret cannot be asked for outside a terminal") {
+		assert.Contains(t, err.Error(), "--no-secret")
+	}
+
+	gock.New("https://api.github.com").
+		Post("repos/lucasmelin/test-repo/hooks").
+		BodyString(`{"name":"web","active":true,"events":["push"],"config":{"url":"https://example.com/webhook"}}`).
+		Reply(201).
+		JSON(`{"id": 2, "name": "web", "active": true, "events": ["push"], "config": {"url": "https://example.com/webhook"}}`)
+	created, err := recreateHook(repo, recorded, 7, true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, created.Id)
+	assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+
+	assert.Equal(t, redactedSecret, recreatedHook(recorded, false).Config.Secret, "the policy sees that a secret will be entered")
+	assert.Empty(t, recreatedHook(recorded, true).Config.Secret)
+}
@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lucasmelin/gh-hook/tui"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdEventsAdd() *cobra.Command {
+	var addCmd = &cobra.Command{
+		Use:          "add <id> <event>...",
+		Short:        "Subscribe a repository webhook to more events.",
+		Args:         cobra.MinimumNArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return changeHookEvents(cmd, args[0], args[1:], nil, eventsOptionsFromFlags(cmd))
+		},
+	}
+	addCmd.Flags().Bool("dry-run", false, "Show the events that would be added without changing the webhook.")
+	addCmd.Flags().Bool("force", false, "Add the events even if the webhook then violates the policy.")
+	addCmd.Flags().String("policy", "", "Check the webhook against this policy file instead of gh-hook/policy.json in the gh config directory.")
+	addTargetFlags(addCmd)
+	return addCmd
+}
+
+func NewCmdEventsRemove() *cobra.Command {
+	var removeCmd = &cobra.Command{
+		Use:          "remove <id> <event>...",
+		Short:        "Unsubscribe a repository webhook from events.",
+		Args:         cobra.MinimumNArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return changeHookEvents(cmd, args[0], nil, args[1:], eventsOptionsFromFlags(cmd))
+		},
+	}
+	removeCmd.Flags().Bool("dry-run", false, "Show the events that would be removed without changing the webhook.")
+	removeCmd.Flags().Bool("force", false, "Remove the events even if the webhook then violates the policy.")
+	removeCmd.Flags().String("policy", "", "Check the webhook against this policy file instead of gh-hook/policy.json in the gh config directory.")
+	addTargetFlags(removeCmd)
+	return removeCmd
+}
+
+func NewCmdEventsEdit() *cobra.Command {
+	var editCmd = &cobra.Command{
+		Use:          "edit [<id>]",
+		Short:        "Choose the events of a repository webhook, starting from its current ones.",
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := getTarget(cmd)
+			if err != nil {
+				return err
+			}
+			if err := checkTarget(target, "events"); err != nil {
+				return err
+			}
+			opts := eventsOptionsFromFlags(cmd)
+
+			var hookId string
+			if len(args) > 0 {
+				hookId = args[0]
+			} else {
+				currentHooks, err := getWebhooks(target)
+				if err != nil {
+					return fmt.Errorf("could not get webhooks: %w\n", err)
+				}
+				if len(currentHooks) == 0 {
+					fmt.Printf("%s has no webhooks\n", targetName(target))
+					return nil
+				}
+				choice, err := tui.ChooseOne("Which webhook's events would you like to edit?", formatHookChoices(currentHooks))
+				if err != nil {
+					return fmt.Errorf("could not choose webhook: %w", err)
+				}
+				hookId = hookIdsFromChoices([]string{choice})[0]
+			}
+
+			current, err := getWebhook(target, hookId)
+			if err != nil {
+				return fmt.Errorf("could not get webhook: %w\n", err)
+			}
+			events, err := getEvents(false, targetKind(target), target.Host())
+			if err != nil {
+				return fmt.Errorf("could not get events: %w\n", err)
+			}
+			choices, descriptions := eventChoices(events)
+			// Keep events the catalog does not offer, so that they are not
+			// removed just because they cannot be shown.
+			for _, event := range current.Events {
+				if !contains(choices, event) {
+					choices = append(choices, event)
+				}
+			}
+			chosen, err := tui.Choose(
+				fmt.Sprintf("Which events should webhook %s receive?", hookId),
+				choices,
+				tui.WithDescriptions(descriptions),
+				tui.WithSelected(current.Events),
+			)
+			if err != nil {
+				return fmt.Errorf("could not choose events: %w", err)
+			}
+			if !chosen.Changed {
+				fmt.Println("The events of the webhook were not changed")
+				return nil
+			}
+			updated, err := expandEvents(chosen.Values, choices)
+			if err != nil {
+				return err
+			}
+			if len(updated) == 0 {
+				return fmt.Errorf("a webhook needs at least one event, use `gh hook delete` to remove it\n")
+			}
+			add, remove := diffEvents(current.Events, updated)
+			if len(add) == 0 && len(remove) == 0 {
+				fmt.Println("The events of the webhook were not changed")
+				return nil
+			}
+			return applyEventChanges(target, current, add, remove, opts)
+		},
+	}
+	editCmd.Flags().Bool("dry-run", false, "Show the events that would change without changing the webhook.")
+	editCmd.Flags().Bool("force", false, "Change the events even if the webhook then violates the policy.")
+	editCmd.Flags().String("policy", "", "Check the webhook against this policy file instead of gh-hook/policy.json in the gh config directory.")
+	addTargetFlags(editCmd)
+	return editCmd
+}
+
+// eventsOptions are the flags of the events add, remove and edit commands.
+type eventsOptions struct {
+	// dryRun only shows the changes.
+	dryRun bool
+	// force saves hooks that violate the policy.
+	force bool
+	// policy is the policy file, or empty for the default one.
+	policy string
+}
+
+func eventsOptionsFromFlags(cmd *cobra.Command) eventsOptions {
+	var opts eventsOptions
+	opts.dryRun, _ = cmd.Flags().GetBool("dry-run")
+	opts.force, _ = cmd.Flags().GetBool("force")
+	opts.policy, _ = cmd.Flags().GetString("policy")
+	return opts
+}
+
+// changeHookEvents adds and removes events given as patterns on the command
+// line.
+func changeHookEvents(cmd *cobra.Command, hookId string, addPatterns, removePatterns []string, opts eventsOptions) error {
+	target, err := getTarget(cmd)
+	if err != nil {
+		return err
+	}
+	if err := checkTarget(target, "events"); err != nil {
+		return err
+	}
+	events, err := getEvents(false, targetKind(target), target.Host())
+	if err != nil {
+		return fmt.Errorf("could not get events: %w\n", err)
+	}
+	names := eventNames(events)
+	add, err := expandEvents(addPatterns, names)
+	if err != nil {
+		return err
+	}
+	current, err := getWebhook(target, hookId)
+	if err != nil {
+		return fmt.Errorf("could not get webhook: %w\n", err)
+	}
+	var remove []string
+	if len(removePatterns) > 0 {
+		remove, err = expandRemovals(hookId, removePatterns, current.Events)
+		if err != nil {
+			return err
+		}
+	}
+	return applyEventChanges(target, current, add, remove, opts)
+}
+
+// expandRemovals expands the patterns of events to remove against the events
+// the hook receives, like events edit, so that events missing from the catalog
+// can be removed too.
+func expandRemovals(hookId string, patterns, current []string) ([]string, error) {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		isPattern := pattern != allEvents && strings.ContainsAny(pattern, "*?[")
+		if pattern == "" || isPattern || strings.HasPrefix(pattern, groupPrefix) {
+			continue
+		}
+		if _, isGroup := eventGroup(pattern, current); !isGroup && !contains(current, pattern) {
+			return nil, fmt.Errorf("webhook %s does not receive %q\n", hookId, pattern)
+		}
+	}
+	return expandEvents(patterns, current)
+}
+
+// applyEventChanges checks the hook with its changed events against the
+// policy, and updates it.
+func applyEventChanges(target hookTarget, current Hook, add, remove []string, opts eventsOptions) error {
+	hookId := strconv.Itoa(current.Id)
+	checked := current
+	checked.Events = changedEvents(current.Events, add, remove)
+	if err := enforcePolicy(opts.policy, opts.force, checked); err != nil {
+		return err
+	}
+	if opts.dryRun {
+		if len(add) > 0 {
+			fmt.Printf("Would add to hook %s: %s\n", hookId, strings.Join(add, ", "))
+		}
+		if len(remove) > 0 {
+			fmt.Printf("Would remove from hook %s: %s\n", hookId, strings.Join(remove, ", "))
+		}
+		return nil
+	}
+	updated, err := updateHookEvents(target, current, add, remove)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Hook %s now receives %s 📬\n", hookId, strings.Join(updated.Events, ", "))
+	return nil
+}
+
+// diffEvents returns the events to add to and remove from current to get
+// updated.
+func diffEvents(current, updated []string) (add, remove []string) {
+	for _, event := range updated {
+		if !contains(current, event) {
+			add = append(add, event)
+		}
+	}
+	for _, event := range current {
+		if !contains(updated, event) {
+			remove = append(remove, event)
+		}
+	}
+	return add, remove
+}
+
+// changedEvents returns the events of a hook receiving current once add are
+// added and remove are removed.
+func changedEvents(current, add, remove []string) []string {
+	var events []string
+	for _, event := range append(append([]string{}, current...), add...) {
+		if !contains(remove, event) && !contains(events, event) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// hookEventsUpdate is the body of a request changing the events of a hook
+// without replacing them all.
+type hookEventsUpdate struct {
+	AddEvents    []string `json:"add_events,omitempty"`
+	RemoveEvents []string `json:"remove_events,omitempty"`
+}
+
+// updateHookEvents subscribes the current hook to the add events and
+// unsubscribes it from the remove events, returning the updated hook. Global
+// webhooks cannot add or remove single events, so their events are replaced
+// instead.
+func updateHookEvents(target hookTarget, current Hook, add, remove []string) (Hook, error) {
+	if err := checkTarget(target, "events"); err != nil {
+		return Hook{}, err
+	}
+	client, err := restClient(target)
+	if err != nil {
+		return Hook{}, fmt.Errorf("error creating REST client: %w\n", err)
+	}
+	var body interface{} = hookEventsUpdate{AddEvents: add, RemoveEvents: remove}
+	if _, ok := target.(enterpriseTarget); ok {
+		body = hookUpdate{Active: current.Active, Events: changedEvents(current.Events, add, remove)}
+	}
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return Hook{}, fmt.Errorf("could not convert events to JSON: %w\n", err)
+	}
+	updated := Hook{}
+	apiUrl := hookPath(target, strconv.Itoa(current.Id))
+	if err := client.Patch(apiUrl, bytes.NewBuffer(jsonData), &updated); err != nil {
+		return Hook{}, fmt.Errorf("could not update webhook events: %w\n", err)
+	}
+	recordHistory(target, "events", &current, &updated)
+	return updated, nil
+}
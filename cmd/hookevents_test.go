@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cli/go-gh/pkg/repository"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_diffEvents(t *testing.T) {
+	add, remove := diffEvents([]string{"push", "release"}, []string{"push", "workflow_run"})
+	assert.Equal(t, []string{"workflow_run"}, add)
+	assert.Equal(t, []string{"release"}, remove)
+
+	add, remove = diffEvents([]string{"push"}, []string{"push"})
+	assert.Empty(t, add)
+	assert.Empty(t, remove)
+}
+
+func Test_updateHookEvents(t *testing.T) {
+	tests := []struct {
+		name      string
+		repo      repository.Repository
+		current   Hook
+		add       []string
+		remove    []string
+		httpMocks func()
+		want      Hook
+		wantErr   bool
+	}{
+		{
+			name: "add event",
+			repo: MockRepo{
+				host:  "github.com",
+				name:  "test-repo",
+				owner: "lucasmelin",
+			},
+			current: Hook{Id: 1, Active: true, Events: []string{"push"}, Config: HookConfig{Url: "https://example.com/webhook"}},
+			add:     []string{"workflow_run"},
+			httpMocks: func() {
+				gock.New("https://api.github.com").
+					Patch("repos/lucasmelin/test-repo/hooks/1").
+					BodyString(`{"add_events":["workflow_run"]}`).
+					Reply(200).
+					JSON(`{"id": 1, "active": true, "events": ["push", "workflow_run"]}`)
+			},
+			want: Hook{Id: 1, Active: true, Events: []string{"push", "workflow_run"}},
+		},
+		{
+			name: "remove event",
+			repo: MockRepo{
+				host:  "github.com",
+				name:  "test-repo",
+				owner: "lucasmelin",
+			},
+			current: Hook{Id: 1, Active: true, Events: []string{"push"}, Config: HookConfig{Url: "https://example.com/webhook"}},
+			remove:  []string{"push"},
+			httpMocks: func() {
+				gock.New("https://api.github.com").
+					Patch("repos/lucasmelin/test-repo/hooks/1").
+					BodyString(`{"remove_events":["push"]}`).
+					Reply(200).
+					JSON(`{"id": 1, "active": true, "events": ["workflow_run"]}`)
+			},
+			want: Hook{Id: 1, Active: true, Events: []string{"workflow_run"}},
+		},
+		{
+			name: "update fails",
+			repo: MockRepo{
+				host:  "github.com",
+				name:  "test-repo",
+				owner: "lucasmelin",
+			},
+			current: Hook{Id: 2, Active: true, Events: []string{"release"}},
+			add:     []string{"push"},
+			httpMocks: func() {
+				gock.New("https://api.github.com").
+					Patch("repos/lucasmelin/test-repo/hooks/2").
+					Reply(404).
+					JSON(`{"message": "Not Found"}`)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubConfig(t, testConfig())
+			t.Cleanup(gock.Off)
+			if tt.httpMocks != nil {
+				tt.httpMocks()
+			}
+			got, err := updateHookEvents(tt.repo, tt.current, tt.add, tt.remove)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("updateHookEvents() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+		})
+	}
+}
+
+func Test_applyEventChangesPolicy(t *testing.T) {
+	stubConfig(t, testConfig())
+	t.Cleanup(gock.Off)
+	path := filepath.Join(t.TempDir(), "policy.json")
+	writePolicy(t, path, `{"forbid_all_events": true}`)
+	repo := MockRepo{host: "github.com", name: "test-repo", owner: "lucasmelin"}
+	current := Hook{Id: 1, Active: true, Events: []string{"push"}, Config: HookConfig{Url: "https://example.com/webhook"}}
+
+	err := applyEventChanges(repo, current, []string{"*"}, nil, eventsOptions{policy: path})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "all-events")
+	}
+
+	gock.New("https://api.github.com").
+		Patch("repos/lucasmelin/test-repo/hooks/1").
+		BodyString(`{"add_events":["*"]}`).
+		Reply(200).
+		JSON(`{"id": 1, "active": true, "events": ["*"]}`)
+	assert.NoError(t, applyEventChanges(repo, current, []string{"*"}, nil, eventsOptions{policy: path, force: true}))
+	assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+}
+
+func Test_expandRemovals(t *testing.T) {
+	current := []string{"push", "pull_request", "pull_request_review", "legacy_event"}
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "event missing from the catalog",
+			patterns: []string{"legacy_event"},
+			want:     []string{"legacy_event"},
+		},
+		{
+			name:     "pattern only matches the events of the hook",
+			patterns: []string{"pull_request*"},
+			want:     []string{"pull_request", "pull_request_review"},
+		},
+		{
+			name:     "event the hook does not receive",
+			patterns: []string{"release"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandRemovals("12345678", tt.patterns, current)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expandRemovals() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,336 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/pkg/tableprinter"
+	"github.com/cli/go-gh/pkg/term"
+	"github.com/spf13/cobra"
+)
+
+// Output formats of lint and audit findings.
+const (
+	formatText  = "text"
+	formatJSON  = "json"
+	formatSARIF = "sarif"
+)
+
+func NewCmdLint() *cobra.Command {
+	var lintCmd = &cobra.Command{
+		Use:          "lint",
+		Short:        "Find duplicate, overlapping, stale and insecure webhooks.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("format")
+			inactiveDays, _ := cmd.Flags().GetInt("inactive-days")
+			if format != formatText && format != formatJSON && format != formatSARIF {
+				return fmt.Errorf("unknown format %q, use text, json or sarif\n", format)
+			}
+
+			repos, err := getRepos(cmd)
+			if err != nil {
+				return err
+			}
+			policyFile, _ := cmd.Flags().GetString("policy")
+			rules := lintRules(inactiveDays)
+			p, err := loadPolicy(policyFile)
+			if err != nil {
+				return err
+			}
+			if p != nil {
+				rules = mergeRules(rules, p.rules())
+			}
+			now := time.Now()
+			var findings []finding
+			for _, repo := range repos {
+				name := repo.Owner() + "/" + repo.Name()
+				hooks, err := getWebhooks(repo)
+				if isNotFound(err) {
+					fmt.Fprintf(os.Stderr, "Skipping %s, its webhooks could not be read\n", name)
+					continue
+				}
+				if err != nil {
+					return fmt.Errorf("could not get webhooks of %s: %w\n", name, err)
+				}
+				findings = append(findings, runRules(rules, name, hooks, now)...)
+			}
+			sortFindings(findings)
+
+			switch format {
+			case formatJSON:
+				err = writeFindingsJSON(os.Stdout, findings)
+			case formatSARIF:
+				err = writeSARIF(os.Stdout, rules, findings)
+			default:
+				err = printFindings(term.FromEnv(), findings)
+			}
+			if err != nil {
+				return err
+			}
+			if hasErrors(findings) {
+				return fmt.Errorf("found webhooks with errors\n")
+			}
+			return nil
+		},
+	}
+	lintCmd.Flags().String("owner", "", "Lint the webhooks of every repository of this organization or user.")
+	lintCmd.Flags().String("format", formatText, "Output format: text, json or sarif.")
+	lintCmd.Flags().String("policy", "", "Also report violations of this policy file, instead of gh-hook/policy.json in the gh config directory.")
+	lintCmd.Flags().Int("inactive-days", 90, "Flag inactive webhooks that have not been updated for this many days.")
+	return lintCmd
+}
+
+// lintRules are the checks run by lint.
+func lintRules(inactiveDays int) []rule {
+	return []rule{
+		{
+			ID:          "duplicate-url",
+			Description: "Several webhooks deliver to the same URL.",
+			Severity:    severityError,
+			Check: func(ctx ruleContext, hook Hook) string {
+				var duplicates []string
+				for _, other := range ctx.Hooks {
+					if other.Id != hook.Id && normalizeUrl(other.Config.Url) == normalizeUrl(hook.Config.Url) {
+						duplicates = append(duplicates, strconv.Itoa(other.Id))
+					}
+				}
+				if len(duplicates) == 0 {
+					return ""
+				}
+				return fmt.Sprintf("same URL as hook %s", strings.Join(duplicates, ", "))
+			},
+		},
+		{
+			ID:          "overlapping-events",
+			Description: "Several webhooks deliver the same events to the same host.",
+			Severity:    severityWarning,
+			Check: func(ctx ruleContext, hook Hook) string {
+				var overlaps []string
+				for _, other := range ctx.Hooks {
+					if other.Id == hook.Id || hookHost(other) != hookHost(hook) ||
+						normalizeUrl(other.Config.Url) == normalizeUrl(hook.Config.Url) {
+						continue
+					}
+					if shared := sharedEvents(hook.Events, other.Events); len(shared) > 0 {
+						overlaps = append(overlaps, fmt.Sprintf("%s with hook %d", strings.Join(shared, ", "), other.Id))
+					}
+				}
+				if len(overlaps) == 0 {
+					return ""
+				}
+				return fmt.Sprintf("%s also receives %s", hookHost(hook), strings.Join(overlaps, "; "))
+			},
+		},
+		{
+			ID:          "stale-inactive",
+			Description: "Inactive webhooks that have not been updated for a long time.",
+			Severity:    severityNote,
+			Check: func(ctx ruleContext, hook Hook) string {
+				if hook.Active {
+					return ""
+				}
+				updated, err := time.Parse(time.RFC3339, hook.UpdatedAt)
+				if err != nil {
+					return ""
+				}
+				days := int(ctx.Now.Sub(updated).Hours() / 24)
+				if days < inactiveDays {
+					return ""
+				}
+				return fmt.Sprintf("inactive and not updated for %d days", days)
+			},
+		},
+		insecureSSLRule,
+		httpUrlRule,
+		missingSecretRule,
+	}
+}
+
+// The rules below are shared by lint, audit and policies.
+
+var insecureSSLRule = rule{
+	ID:          "insecure-ssl",
+	Description: "SSL verification is disabled.",
+	Severity:    severityError,
+	Check: func(ctx ruleContext, hook Hook) string {
+		if hook.Config.InsecureSSL != "1" {
+			return ""
+		}
+		return "SSL certificate verification is disabled"
+	},
+}
+
+var httpUrlRule = rule{
+	ID:          "http-url",
+	Description: "Payloads are sent over plain HTTP.",
+	Severity:    severityError,
+	Check: func(ctx ruleContext, hook Hook) string {
+		if !strings.HasPrefix(strings.ToLower(hook.Config.Url), "http://") {
+			return ""
+		}
+		return "payloads are sent unencrypted over http"
+	},
+}
+
+var missingSecretRule = rule{
+	ID:          "missing-secret",
+	Description: "Payloads are not signed with a secret.",
+	Severity:    severityWarning,
+	Check: func(ctx ruleContext, hook Hook) string {
+		if hook.Config.Secret != "" {
+			return ""
+		}
+		return "no secret is configured, so payloads cannot be verified"
+	},
+}
+
+// sortFindings orders findings by severity, repository and hook.
+func sortFindings(findings []finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Severity != b.Severity {
+			return severityOrder[a.Severity] < severityOrder[b.Severity]
+		}
+		if a.Repo != b.Repo {
+			return a.Repo < b.Repo
+		}
+		return a.HookId < b.HookId
+	})
+}
+
+func printFindings(t term.Term, findings []finding) error {
+	if len(findings) == 0 {
+		fmt.Fprintln(t.Out(), "No problems found ✨")
+		return nil
+	}
+	width, _, err := t.Size()
+	if err != nil {
+		width = 80
+	}
+	tp := tableprinter.New(t.Out(), t.IsTerminalOutput(), width)
+	if t.IsTerminalOutput() {
+		for _, header := range []string{"SEVERITY", "RULE", "REPO", "HOOK", "URL", "MESSAGE"} {
+			tp.AddField(header)
+		}
+		tp.EndRow()
+	}
+	for _, f := range findings {
+		tp.AddField(string(f.Severity))
+		tp.AddField(f.Rule)
+		tp.AddField(f.Repo)
+		tp.AddField(strconv.Itoa(f.HookId))
+		tp.AddField(f.Url)
+		tp.AddField(f.Message)
+		tp.EndRow()
+	}
+	return tp.Render()
+}
+
+func writeFindingsJSON(w io.Writer, findings []finding) error {
+	if findings == nil {
+		findings = []finding{}
+	}
+	data, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not convert findings to JSON: %w\n", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// The types below are the parts of the SARIF 2.1.0 format used for findings.
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationUri string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level severity `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleId    string          `json:"ruleId"`
+	Level     severity        `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+func writeSARIF(w io.Writer, rules []rule, findings []finding) error {
+	driver := sarifDriver{
+		Name:           "gh-hook",
+		InformationUri: "https://github.com/lucasmelin/gh-hook",
+	}
+	for _, r := range rules {
+		driver.Rules = append(driver.Rules, sarifRule{
+			Id:                   r.ID,
+			ShortDescription:     sarifMessage{Text: r.Description},
+			DefaultConfiguration: sarifConfiguration{Level: r.Severity},
+		})
+	}
+	results := []sarifResult{}
+	for _, f := range findings {
+		results = append(results, sarifResult{
+			RuleId:  f.Rule,
+			Level:   f.Severity,
+			Message: sarifMessage{Text: fmt.Sprintf("Hook %d (%s): %s", f.HookId, f.Url, f.Message)},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{
+					FullyQualifiedName: fmt.Sprintf("%s/hooks/%d", f.Repo, f.HookId),
+					Kind:               "resource",
+				}},
+			}},
+		})
+	}
+	data, err := json.MarshalIndent(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not convert findings to SARIF: %w\n", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
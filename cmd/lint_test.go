@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_lintRules(t *testing.T) {
+	now := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	hooks := []Hook{
+		{
+			Id:     1,
+			Active: true,
+			Events: []string{"push"},
+			Config: HookConfig{Url: "https://ci.example.com/hook", InsecureSSL: "0", Secret: "********"},
+		},
+		{
+			Id:     2,
+			Active: true,
+			Events: []string{"push", "release"},
+			Config: HookConfig{Url: "https://ci.example.com/hook/", InsecureSSL: "0", Secret: "********"},
+		},
+		{
+			Id:     3,
+			Active: true,
+			Events: []string{"*"},
+			Config: HookConfig{Url: "https://ci.example.com/other", InsecureSSL: "0", Secret: "********"},
+		},
+		{
+			Id:        4,
+			Active:    false,
+			Events:    []string{"issues"},
+			Config:    HookConfig{Url: "http://chat.example.org", InsecureSSL: "1"},
+			UpdatedAt: "2022-01-01T00:00:00Z",
+		},
+	}
+	findings := runRules(lintRules(90), "octocat/hello", hooks, now)
+	sortFindings(findings)
+
+	type result struct {
+		rule   string
+		hookId int
+	}
+	var got []result
+	for _, f := range findings {
+		assert.Equal(t, "octocat/hello", f.Repo)
+		got = append(got, result{f.Rule, f.HookId})
+	}
+	assert.Equal(t, []result{
+		{"duplicate-url", 1},
+		{"duplicate-url", 2},
+		{"insecure-ssl", 4},
+		{"http-url", 4},
+		{"overlapping-events", 1},
+		{"overlapping-events", 2},
+		{"overlapping-events", 3},
+		{"missing-secret", 4},
+		{"stale-inactive", 4},
+	}, got)
+	assert.Equal(t, "ci.example.com also receives push with hook 3", findings[4].Message)
+	assert.True(t, hasErrors(findings))
+}
+
+func Test_writeSARIF(t *testing.T) {
+	findings := []finding{{Rule: "http-url", Severity: severityError, Repo: "octocat/hello", HookId: 4, Url: "http://example.org", Message: "payloads are sent unencrypted over http"}}
+	var out bytes.Buffer
+	assert.NoError(t, writeSARIF(&out, lintRules(90), findings))
+
+	var log sarifLog
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	assert.Len(t, log.Runs, 1)
+	assert.Len(t, log.Runs[0].Tool.Driver.Rules, 6)
+	assert.Equal(t, "http-url", log.Runs[0].Results[0].RuleId)
+	assert.Equal(t, "octocat/hello/hooks/4", log.Runs[0].Results[0].Locations[0].LogicalLocations[0].FullyQualifiedName)
+}
+
+func Test_writeFindingsJSON(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, writeFindingsJSON(&out, nil))
+	assert.Equal(t, "[]\n", out.String())
+}
@@ -2,44 +2,172 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"sort"
 	"strconv"
 	"strings"
 
-	"github.com/cli/go-gh"
-	"github.com/cli/go-gh/pkg/api"
-	"github.com/cli/go-gh/pkg/repository"
+	"github.com/cli/go-gh/pkg/tableprinter"
+	"github.com/cli/go-gh/pkg/term"
 	"github.com/spf13/cobra"
 )
 
 func NewCmdList() *cobra.Command {
 	var listCmd = &cobra.Command{
-		Use:   "list",
-		Short: "List all repository webhooks.",
+		Use:          "list",
+		Short:        "List all repository webhooks.",
+		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			repo, err := getRepo(cmd)
+			target, err := getTarget(cmd)
 			if err != nil {
 				return err
 			}
 
-			currentHooks, err := getWebhooks(repo)
+			columns, _ := cmd.Flags().GetStringSlice("columns")
+			sortKey, _ := cmd.Flags().GetString("sort")
+			if err := validColumns(columns); err != nil {
+				return err
+			}
+			if sortKey != "" {
+				if err := validColumns([]string{strings.TrimPrefix(sortKey, "-")}); err != nil {
+					return err
+				}
+			}
+
+			currentHooks, err := getWebhooks(target)
 			if err != nil {
 				return fmt.Errorf("could not get webhooks: %w\n", err)
 			}
-			choices := formatHookChoices(currentHooks)
-			if len(choices) == 0 {
-				fmt.Printf("%s/%s has no webhooks\n", repo.Owner(), repo.Name())
+			if len(currentHooks) == 0 {
+				fmt.Printf("%s has no webhooks\n", targetName(target))
 				return nil
 			}
-			for _, hook := range choices {
-				fmt.Println(hook)
+			currentHooks = filterHooks(currentHooks, filterFromFlags(cmd))
+			if len(currentHooks) == 0 {
+				fmt.Println("No webhooks match the filters")
+				return nil
 			}
+			sortHooks(currentHooks, sortKey)
 
-			return nil
+			t := term.FromEnv()
+			width, _, err := t.Size()
+			if err != nil {
+				width = 80
+			}
+			return printHooks(t.Out(), t.IsTerminalOutput(), width, currentHooks, columns)
 		},
 	}
+	listCmd.Flags().StringSlice("columns", listColumnNames, "Columns to show: "+strings.Join(listColumnNames, ", ")+".")
+	addFilterFlags(listCmd, "list")
+	listCmd.Flags().String("sort", "", "Sort by a column, in descending order when prefixed with \"-\", such as \"-updated\".")
+	addTargetFlags(listCmd)
 	return listCmd
 }
 
+// listColumn is a column of the list table.
+type listColumn struct {
+	header string
+	value  func(Hook) string
+	// less compares hooks by the column, when comparing the values as text
+	// would be wrong.
+	less func(a, b Hook) bool
+}
+
+// listColumnNames are the columns of the list table, in their default order.
+var listColumnNames = []string{"id", "active", "url", "content-type", "events", "status", "updated"}
+
+var listColumns = map[string]listColumn{
+	"id": {
+		header: "ID",
+		value:  func(h Hook) string { return strconv.Itoa(h.Id) },
+		less:   func(a, b Hook) bool { return a.Id < b.Id },
+	},
+	"active": {
+		header: "ACTIVE",
+		value:  func(h Hook) string { return strconv.FormatBool(h.Active) },
+	},
+	"url": {
+		header: "URL",
+		value:  func(h Hook) string { return h.Config.Url },
+	},
+	"content-type": {
+		header: "CONTENT TYPE",
+		value:  func(h Hook) string { return h.Config.ContentType },
+	},
+	"events": {
+		header: "EVENTS",
+		value:  func(h Hook) string { return strconv.Itoa(len(h.Events)) },
+		less:   func(a, b Hook) bool { return len(a.Events) < len(b.Events) },
+	},
+	"status": {
+		header: "LAST STATUS",
+		value:  lastResponseStatus,
+	},
+	"updated": {
+		header: "UPDATED",
+		value:  func(h Hook) string { return h.UpdatedAt },
+	},
+}
+
+func validColumns(columns []string) error {
+	for _, column := range columns {
+		if _, ok := listColumns[column]; !ok {
+			return fmt.Errorf("unknown column %q, available columns: %s\n", column, strings.Join(listColumnNames, ", "))
+		}
+	}
+	return nil
+}
+
+// sortHooks sorts hooks by a column, in descending order when the key is
+// prefixed with "-". An empty key keeps the order of the API.
+func sortHooks(hooks []Hook, key string) {
+	column, ok := listColumns[strings.TrimPrefix(key, "-")]
+	if !ok {
+		return
+	}
+	less := column.less
+	if less == nil {
+		less = func(a, b Hook) bool { return column.value(a) < column.value(b) }
+	}
+	descending := strings.HasPrefix(key, "-")
+	sort.SliceStable(hooks, func(i, j int) bool {
+		if descending {
+			return less(hooks[j], hooks[i])
+		}
+		return less(hooks[i], hooks[j])
+	})
+}
+
+// printHooks prints the columns of hooks as a table that fits the terminal,
+// or as tab-separated values without headers when not printing to one.
+func printHooks(w io.Writer, isTTY bool, width int, hooks []Hook, columns []string) error {
+	tp := tableprinter.New(w, isTTY, width)
+	if isTTY {
+		for _, column := range columns {
+			tp.AddField(listColumns[column].header)
+		}
+		tp.EndRow()
+	}
+	for _, hook := range hooks {
+		for _, column := range columns {
+			tp.AddField(listColumns[column].value(hook))
+		}
+		tp.EndRow()
+	}
+	return tp.Render()
+}
+
+// lastResponseStatus describes the last response the hook received.
+func lastResponseStatus(hook Hook) string {
+	if hook.LastResponse == nil {
+		return ""
+	}
+	if hook.LastResponse.Code != 0 {
+		return fmt.Sprintf("%d %s", hook.LastResponse.Code, hook.LastResponse.Status)
+	}
+	return hook.LastResponse.Status
+}
+
 func formatHookChoices(currentHooks []Hook) []string {
 	var choices []string
 	for _, choice := range currentHooks {
@@ -61,19 +189,96 @@ func formatHookChoices(currentHooks []Hook) []string {
 	return choices
 }
 
-func getWebhooks(repo repository.Repository) ([]Hook, error) {
-	hookOpts := api.ClientOptions{
-		Host: repo.Host(),
+// hookIdsFromChoices extracts the hook IDs from choices produced by
+// formatHookChoices.
+func hookIdsFromChoices(choices []string) []string {
+	var ids []string
+	for _, choice := range choices {
+		_, withoutPrefix, _ := strings.Cut(choice, " ")
+		id, _, _ := strings.Cut(withoutPrefix, " ")
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// selectHooks returns the hooks with the given IDs, in the order of the IDs.
+func selectHooks(hooks []Hook, hookIds []string) ([]Hook, error) {
+	var selected []Hook
+	for _, hookId := range hookIds {
+		found := false
+		for _, hook := range hooks {
+			if strconv.Itoa(hook.Id) == hookId {
+				selected = append(selected, hook)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no webhook with ID %s\n", hookId)
+		}
 	}
-	client, err := gh.RESTClient(&hookOpts)
+	return selected, nil
+}
+
+// idsOfHooks returns the IDs of hooks as strings, as taken by the API helpers.
+func idsOfHooks(hooks []Hook) []string {
+	var ids []string
+	for _, hook := range hooks {
+		ids = append(ids, strconv.Itoa(hook.Id))
+	}
+	return ids
+}
+
+// hooksPerPage is the page size used when listing webhooks, which is the most
+// the API allows.
+const hooksPerPage = 100
+
+// getWebhooks returns every webhook of target, going through all the pages. A
+// GitHub App has a single webhook, which is returned on its own.
+func getWebhooks(target hookTarget) ([]Hook, error) {
+	if _, ok := target.(appTarget); ok {
+		hook, err := getWebhook(target, "")
+		if err != nil {
+			return nil, err
+		}
+		return []Hook{hook}, nil
+	}
+	client, err := restClient(target)
 	if err != nil {
 		return nil, err
 	}
-	response := []Hook{}
-	apiUrl := fmt.Sprintf("repos/%s/%s/hooks", repo.Owner(), repo.Name())
-	err = client.Get(apiUrl, &response)
+	hooks := []Hook{}
+	for page := 1; ; page++ {
+		var response []Hook
+		apiUrl := fmt.Sprintf("%s?per_page=%d&page=%d", hooksPath(target), hooksPerPage, page)
+		if err := client.Get(apiUrl, &response); err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, response...)
+		if len(response) < hooksPerPage {
+			return hooks, nil
+		}
+	}
+}
+
+func getWebhook(target hookTarget, hookId string) (Hook, error) {
+	client, err := restClient(target)
 	if err != nil {
-		return nil, err
+		return Hook{}, err
+	}
+	response := Hook{}
+	apiUrl := hookPath(target, hookId)
+	if _, ok := target.(appTarget); ok {
+		// The API only offers the configuration of an app webhook, whose
+		// events and state are app settings.
+		response = Hook{Name: "app", Active: true}
+		if err := client.Get(apiUrl+"/config", &response.Config); err != nil {
+			return Hook{}, err
+		}
+		return response, nil
+	}
+	if err := client.Get(apiUrl, &response); err != nil {
+		return Hook{}, err
 	}
 	return response, nil
 }
@@ -105,10 +105,47 @@ func Test_getWebhooks(t *testing.T) {
 						InsecureSSL: "0",
 						Url:         "https://example.com/webhook",
 					},
+					CreatedAt: "2019-06-03T00:57:16Z",
+					UpdatedAt: "2019-06-03T00:57:16Z",
+					LastResponse: &HookResponse{
+						Status: "unused",
+					},
 				},
 			},
 			wantErr: false,
 		},
+		{
+			name: "follows pages",
+			repo: MockRepo{
+				host:  "github.com",
+				name:  "Hello-World",
+				owner: "octocat",
+			},
+			httpMocks: func() {
+				page := make([]string, hooksPerPage)
+				for i := range page {
+					page[i] = fmt.Sprintf(`{"id": %d}`, i+1)
+				}
+				gock.New("https://api.github.com").
+					Get("repos/octocat/Hello-World/hooks").
+					MatchParam("per_page", "100").
+					MatchParam("page", "1").
+					Reply(200).
+					JSON("[" + strings.Join(page, ",") + "]")
+				gock.New("https://api.github.com").
+					Get("repos/octocat/Hello-World/hooks").
+					MatchParam("page", "2").
+					Reply(200).
+					JSON(`[{"id": 101}]`)
+			},
+			want: func() []Hook {
+				hooks := make([]Hook, hooksPerPage+1)
+				for i := range hooks {
+					hooks[i] = Hook{Id: i + 1}
+				}
+				return hooks
+			}(),
+		},
 		{
 			name: "support enterprise hosts",
 			repo: MockRepo{
@@ -244,3 +281,77 @@ func Test_formatHookChoices(t *testing.T) {
 		})
 	}
 }
+
+func Test_selectHooks(t *testing.T) {
+	hooks := []Hook{{Id: 1}, {Id: 2}, {Id: 3}}
+
+	selected, err := selectHooks(hooks, []string{"3", "1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Hook{{Id: 3}, {Id: 1}}, selected)
+
+	_, err = selectHooks(hooks, []string{"4"})
+	assert.EqualError(t, err, "no webhook with ID 4\n")
+}
+
+func Test_printHooks(t *testing.T) {
+	hooks := []Hook{
+		{
+			Id:           12345678,
+			Active:       true,
+			Events:       []string{"push", "pull_request"},
+			Config:       HookConfig{Url: "https://example.com/webhook", ContentType: "json"},
+			UpdatedAt:    "2023-03-01T00:00:00Z",
+			LastResponse: &HookResponse{Code: 200, Status: "active"},
+		},
+		{
+			Id:           2,
+			Active:       false,
+			Events:       []string{"*"},
+			Config:       HookConfig{Url: "https://example.org", ContentType: "form"},
+			UpdatedAt:    "2023-02-01T00:00:00Z",
+			LastResponse: &HookResponse{Status: "unused"},
+		},
+	}
+
+	t.Run("tab-separated without a terminal", func(t *testing.T) {
+		var out strings.Builder
+		assert.NoError(t, printHooks(&out, false, 80, hooks, listColumnNames))
+		assert.Equal(t, "12345678\ttrue\thttps://example.com/webhook\tjson\t2\t200 active\t2023-03-01T00:00:00Z\n"+
+			"2\tfalse\thttps://example.org\tform\t1\tunused\t2023-02-01T00:00:00Z\n", out.String())
+	})
+
+	t.Run("aligned table with selected columns", func(t *testing.T) {
+		var out strings.Builder
+		assert.NoError(t, printHooks(&out, true, 80, hooks, []string{"id", "url"}))
+		assert.Equal(t, "ID        URL\n12345678  https://example.com/webhook\n2         https://example.org\n", out.String())
+	})
+}
+
+func Test_sortHooks(t *testing.T) {
+	hooks := []Hook{
+		{Id: 10, Events: []string{"push"}, UpdatedAt: "2023-02-01T00:00:00Z"},
+		{Id: 9, Events: []string{"push", "release", "issues"}, UpdatedAt: "2023-03-01T00:00:00Z"},
+		{Id: 100, Events: []string{"push", "release"}, UpdatedAt: "2023-01-01T00:00:00Z"},
+	}
+	ids := func() []int {
+		var ids []int
+		for _, h := range hooks {
+			ids = append(ids, h.Id)
+		}
+		return ids
+	}
+
+	sortHooks(hooks, "id")
+	assert.Equal(t, []int{9, 10, 100}, ids())
+	sortHooks(hooks, "-events")
+	assert.Equal(t, []int{9, 100, 10}, ids())
+	sortHooks(hooks, "updated")
+	assert.Equal(t, []int{100, 10, 9}, ids())
+	sortHooks(hooks, "")
+	assert.Equal(t, []int{100, 10, 9}, ids())
+}
+
+func Test_validColumns(t *testing.T) {
+	assert.NoError(t, validColumns([]string{"id", "status"}))
+	assert.Error(t, validColumns([]string{"id", "name"}))
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/pkg/config"
+)
+
+// policy is a set of rules that created and edited hooks must follow, read
+// from a JSON file. Lint reports violations of the policy as well.
+type policy struct {
+	RequireHTTPS           bool     `json:"require_https"`
+	RequireSSLVerification bool     `json:"require_ssl_verification"`
+	RequireSecret          bool     `json:"require_secret"`
+	ForbidAllEvents        bool     `json:"forbid_all_events"`
+	AllowedHosts           []string `json:"allowed_hosts,omitempty"`
+	// ForbiddenEvents accepts the same patterns and groups as create --events.
+	ForbiddenEvents []string `json:"forbidden_events,omitempty"`
+}
+
+// policyPath is where the policy is read from when no other file is given.
+func policyPath() string {
+	return filepath.Join(config.ConfigDir(), "gh-hook", "policy.json")
+}
+
+// loadPolicy reads the policy from path, or from policyPath when path is
+// empty. Having no policy at the default path is not an error, and returns a
+// nil policy.
+func loadPolicy(path string) (*policy, error) {
+	if path == "" {
+		path = policyPath()
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open policy: %w\n", err)
+	}
+	defer file.Close()
+	var p policy
+	decoder := json.NewDecoder(file)
+	// Catch misspelled rules, which would otherwise silently not apply.
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&p); err != nil {
+		return nil, fmt.Errorf("could not parse policy %s: %w\n", path, err)
+	}
+	p.ForbiddenEvents, err = expandEvents(p.ForbiddenEvents, eventNames(catalog.Events))
+	if err != nil {
+		return nil, fmt.Errorf("invalid forbidden events in policy %s: %w\n", path, err)
+	}
+	return &p, nil
+}
+
+// rules returns the checks enforcing the policy.
+func (p policy) rules() []rule {
+	var rules []rule
+	if p.RequireHTTPS {
+		rules = append(rules, httpUrlRule)
+	}
+	if p.RequireSSLVerification {
+		rules = append(rules, insecureSSLRule)
+	}
+	if p.RequireSecret {
+		rules = append(rules, missingSecretRule)
+	}
+	if p.ForbidAllEvents {
+		rules = append(rules, rule{
+			ID:          "all-events",
+			Description: "Every event is sent.",
+			Severity:    severityError,
+			Check: func(ctx ruleContext, hook Hook) string {
+				if !contains(hook.Events, allEvents) {
+					return ""
+				}
+				return "subscribing to every event with * is not allowed"
+			},
+		})
+	}
+	if len(p.AllowedHosts) > 0 {
+		rules = append(rules, allowedHostsRule(p.AllowedHosts))
+	}
+	if len(p.ForbiddenEvents) > 0 {
+		rules = append(rules, rule{
+			ID:          "forbidden-events",
+			Description: "Events that the policy forbids are sent.",
+			Severity:    severityError,
+			Check: func(ctx ruleContext, hook Hook) string {
+				shared := sharedEvents(hook.Events, p.ForbiddenEvents)
+				if len(shared) == 0 {
+					return ""
+				}
+				return fmt.Sprintf("events %s are not allowed", strings.Join(shared, ", "))
+			},
+		})
+	}
+	return rules
+}
+
+// enforcePolicy checks hooks against the policy at path, or the default
+// policy. Violations are an error, unless force is set, in which case they are
+// only printed.
+func enforcePolicy(path string, force bool, hooks ...Hook) error {
+	p, err := loadPolicy(path)
+	if err != nil || p == nil {
+		return err
+	}
+	findings := runRules(p.rules(), "", hooks, time.Now())
+	if len(findings) == 0 {
+		return nil
+	}
+	var violations strings.Builder
+	for _, f := range findings {
+		fmt.Fprintf(&violations, "  %s: %s (%s)\n", f.Url, f.Message, f.Rule)
+	}
+	if force {
+		fmt.Fprintf(os.Stderr, "Ignoring policy violations:\n%s", violations.String())
+		return nil
+	}
+	if len(hooks) > 1 {
+		return fmt.Errorf("the webhooks violate the policy, none were saved:\n%sFix the webhooks, or pass --force to ignore the policy\n", violations.String())
+	}
+	return fmt.Errorf("the webhook violates the policy:\n%sFix the webhook, or pass --force to ignore the policy\n", violations.String())
+}
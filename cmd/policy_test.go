@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writePolicy(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_loadPolicy(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("GH_CONFIG_DIR", configDir)
+
+	p, err := loadPolicy("")
+	assert.NoError(t, err)
+	assert.Nil(t, p, "no policy without a policy file")
+
+	writePolicy(t, filepath.Join(configDir, "gh-hook", "policy.json"), `{"require_https": true, "forbidden_events": ["@pr"]}`)
+	p, err = loadPolicy("")
+	assert.NoError(t, err)
+	assert.True(t, p.RequireHTTPS)
+	assert.Contains(t, p.ForbiddenEvents, "pull_request_review")
+
+	other := filepath.Join(t.TempDir(), "policy.json")
+	writePolicy(t, other, `{"require_http": true}`)
+	_, err = loadPolicy(other)
+	assert.Error(t, err, "unknown rules are rejected")
+
+	_, err = loadPolicy(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err, "an explicit policy file must exist")
+}
+
+func Test_enforcePolicy(t *testing.T) {
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+	path := filepath.Join(t.TempDir(), "policy.json")
+	writePolicy(t, path, `{
+  "require_https": true,
+  "require_ssl_verification": true,
+  "require_secret": true,
+  "forbid_all_events": true,
+  "allowed_hosts": ["*.example.com"]
+}`)
+
+	compliant := Hook{
+		Events: []string{"push"},
+		Config: HookConfig{Url: "https://ci.example.com/hook", InsecureSSL: "0", Secret: "s3cr3t"},
+	}
+	assert.NoError(t, enforcePolicy(path, false, compliant))
+
+	violating := Hook{
+		Events: []string{"*"},
+		Config: HookConfig{Url: "http://example.net/hook", InsecureSSL: "1"},
+	}
+	err := enforcePolicy(path, false, violating)
+	assert.Error(t, err)
+	for _, rule := range []string{"http-url", "insecure-ssl", "missing-secret", "all-events", "disallowed-host"} {
+		assert.Contains(t, err.Error(), rule)
+	}
+	assert.NoError(t, enforcePolicy(path, true, violating), "force ignores the policy")
+
+	err = enforcePolicy(path, false, compliant, violating)
+	assert.Error(t, err, "a single violation rejects the whole batch")
+	assert.Contains(t, err.Error(), "none were saved")
+
+	assert.NoError(t, enforcePolicy("", false, violating), "no default policy")
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cli/go-gh"
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/cli/go-gh/pkg/auth"
+	"github.com/cli/go-gh/pkg/repository"
+	"github.com/spf13/cobra"
+)
+
+// reposPerPage is the page size used when listing the repositories of an
+// owner, which is the most the API allows.
+const reposPerPage = 100
+
+// getRepos returns the repositories to scan: every repository of the owner
+// given with --owner, or else the repository given with --repo or the current
+// one.
+func getRepos(cmd *cobra.Command) ([]repository.Repository, error) {
+	owner, _ := cmd.Flags().GetString("owner")
+	if owner == "" {
+		repo, err := getRepo(cmd)
+		if err != nil {
+			return nil, err
+		}
+		return []repository.Repository{repo}, nil
+	}
+	host, _ := auth.DefaultHost()
+	if repo, err := getRepo(cmd); err == nil {
+		host = repo.Host()
+	}
+	repos, err := getOwnerRepos(host, owner)
+	if err != nil {
+		return nil, fmt.Errorf("could not list the repositories of %s: %w\n", owner, err)
+	}
+	return repos, nil
+}
+
+// getOwnerRepos returns the repositories of an organization or user that are
+// not archived.
+func getOwnerRepos(host, owner string) ([]repository.Repository, error) {
+	client, err := gh.RESTClient(&api.ClientOptions{Host: host})
+	if err != nil {
+		return nil, err
+	}
+	reposUrl := fmt.Sprintf("orgs/%s/repos?", owner)
+	var repos []repository.Repository
+	for page := 1; ; page++ {
+		var response []struct {
+			FullName string `json:"full_name"`
+			Archived bool   `json:"archived"`
+		}
+		apiUrl := fmt.Sprintf("%sper_page=%d&page=%d", reposUrl, reposPerPage, page)
+		err := client.Get(apiUrl, &response)
+		if strings.HasPrefix(reposUrl, "orgs/") && page == 1 && isNotFound(err) {
+			// The owner is a user rather than an organization. Only the
+			// public repositories of other users are listed, so the
+			// repositories of the current user are listed from their account.
+			reposUrl = fmt.Sprintf("users/%s/repos?", owner)
+			if strings.EqualFold(owner, currentUser(host)) {
+				reposUrl = "user/repos?affiliation=owner&"
+			}
+			page = 0
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range response {
+			if r.Archived {
+				continue
+			}
+			repo, err := repository.ParseWithHost(r.FullName, host)
+			if err != nil {
+				return nil, err
+			}
+			repos = append(repos, repo)
+		}
+		if len(response) < reposPerPage {
+			return repos, nil
+		}
+	}
+}
+
+// isNotFound reports whether err is a 404 response, which the API also
+// returns when the user cannot administer a repository's webhooks.
+func isNotFound(err error) bool {
+	var httpErr api.HTTPError
+	return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound
+}
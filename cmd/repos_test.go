@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_getOwnerRepos(t *testing.T) {
+	tests := []struct {
+		name     string
+		owner    string
+		userPath string
+		params   map[string]string
+	}{
+		{
+			name:     "other user",
+			owner:    "octocat",
+			userPath: "users/octocat/repos",
+			params:   map[string]string{"page": "1"},
+		},
+		{
+			name:     "current user includes private repositories",
+			owner:    "user1",
+			userPath: "user/repos",
+			params:   map[string]string{"page": "1", "affiliation": "owner"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubConfig(t, testConfig())
+			t.Cleanup(gock.Off)
+			gock.New("https://api.github.com").
+				Get("orgs/" + tt.owner + "/repos").
+				Reply(404).
+				JSON(`{"message": "Not Found"}`)
+			gock.New("https://api.github.com").
+				Get(tt.userPath).
+				MatchParams(tt.params).
+				Reply(200).
+				JSON(`[{"full_name": "` + tt.owner + `/hello"}, {"full_name": "` + tt.owner + `/old", "archived": true}]`)
+
+			repos, err := getOwnerRepos("github.com", tt.owner)
+			assert.NoError(t, err)
+			assert.Len(t, repos, 1)
+			assert.Equal(t, "hello", repos[0].Name())
+			assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+		})
+	}
+}
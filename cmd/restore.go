@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/pkg/tableprinter"
+	"github.com/cli/go-gh/pkg/term"
+	"github.com/lucasmelin/gh-hook/tui"
+	"github.com/spf13/cobra"
+)
+
+// restoreWindow is how long deleted hooks are offered by restore. Older
+// deletes stay in the history and can still be undone from there.
+const restoreWindow = 30 * 24 * time.Hour
+
+// deletedHook is a recorded delete of a hook that can be restored. Secrets
+// are redacted, as the API never returns them.
+type deletedHook struct {
+	// change is the number of the delete in the history.
+	change int
+	entry  historyEntry
+}
+
+// deletedHooksOf returns the deletes of hooks of target recorded in the
+// history within restoreWindow of now that have not been undone, newest first.
+func deletedHooksOf(target hookTarget, entries []historyEntry, now time.Time) []deletedHook {
+	undone := map[int]bool{}
+	for _, entry := range entries {
+		if entry.Undoes > 0 {
+			undone[entry.Undoes] = true
+		}
+	}
+	var deleted []deletedHook
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.Action != "delete" || entry.Before == nil || undone[i+1] {
+			continue
+		}
+		if now.Sub(entry.Time) > restoreWindow {
+			continue
+		}
+		if entry.Host == target.Host() && entry.Scope == targetKind(target) && entry.Target == historyTarget(target) {
+			deleted = append(deleted, deletedHook{change: i + 1, entry: entry})
+		}
+	}
+	return deleted
+}
+
+// selectDeletedHooks returns the deletes of the hooks with the given IDs. The
+// deletes are newest first, so the latest copy of a hook that was deleted
+// more than once is picked.
+func selectDeletedHooks(deleted []deletedHook, hookIds []string) ([]deletedHook, error) {
+	var selected []deletedHook
+	for _, hookId := range hookIds {
+		found := false
+		for _, d := range deleted {
+			if strconv.Itoa(d.entry.HookId) == hookId {
+				selected = append(selected, d)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no recently deleted webhook with ID %s\n", hookId)
+		}
+	}
+	return selected, nil
+}
+
+func NewCmdRestore() *cobra.Command {
+	var restoreCmd = &cobra.Command{
+		Use:          "restore [<id>...]",
+		Short:        "Recreate recently deleted webhooks.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := getTarget(cmd)
+			if err != nil {
+				return err
+			}
+			if err := checkTarget(target, "create"); err != nil {
+				return err
+			}
+			list, _ := cmd.Flags().GetBool("list")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			yes, _ := cmd.Flags().GetBool("yes")
+			force, _ := cmd.Flags().GetBool("force")
+			policyFile, _ := cmd.Flags().GetString("policy")
+			noSecret, _ := cmd.Flags().GetBool("no-secret")
+
+			entries, err := readHistory()
+			if err != nil {
+				return fmt.Errorf("could not read history: %w\n", err)
+			}
+			deleted := deletedHooksOf(target, entries, time.Now())
+			if len(deleted) == 0 {
+				fmt.Printf("No webhooks of %s were deleted in the last %d days\n", targetName(target), int(restoreWindow.Hours()/24))
+				return nil
+			}
+			t := term.FromEnv()
+			if list {
+				width, _, err := t.Size()
+				if err != nil {
+					width = 80
+				}
+				return printDeletedHooks(t.Out(), t.IsTerminalOutput(), width, deleted)
+			}
+
+			hookIds := args
+			if len(hookIds) == 0 {
+				if !t.IsTerminalOutput() {
+					return fmt.Errorf("pass the IDs of the webhooks to restore, see --list\n")
+				}
+				var hooks []Hook
+				for _, d := range deleted {
+					hooks = append(hooks, *d.entry.Before)
+				}
+				chosen, err := tui.ChooseMany("Which webhooks would you like to restore?", formatHookChoices(hooks))
+				if err != nil {
+					return fmt.Errorf("could not choose webhooks: %w", err)
+				}
+				hookIds = hookIdsFromChoices(chosen)
+			}
+			selected, err := selectDeletedHooks(deleted, hookIds)
+			if err != nil {
+				return err
+			}
+			var hooks, recreated []Hook
+			for _, d := range selected {
+				hooks = append(hooks, *d.entry.Before)
+				recreated = append(recreated, recreatedHook(*d.entry.Before, noSecret))
+			}
+			if err := enforcePolicy(policyFile, force, recreated...); err != nil {
+				return err
+			}
+			confirmed, err := confirmHooks(t, "restore", hooks, dryRun, yes)
+			if err != nil || !confirmed {
+				return err
+			}
+			return restoreHooks(target, selected, noSecret)
+		},
+	}
+	restoreCmd.Flags().Bool("list", false, "List the recently deleted webhooks instead of restoring them.")
+	restoreCmd.Flags().Bool("dry-run", false, "Show the webhooks that would be restored without creating them.")
+	restoreCmd.Flags().BoolP("yes", "y", false, "Restore the webhooks without asking for confirmation.")
+	restoreCmd.Flags().Bool("force", false, "Restore the webhooks even if they violate the policy.")
+	restoreCmd.Flags().Bool("no-secret", false, "Restore webhooks that had a secret without one, instead of asking for it.")
+	restoreCmd.Flags().String("policy", "", "Check the webhooks against this policy file instead of gh-hook/policy.json in the gh config directory.")
+	addTargetFlags(restoreCmd)
+	return restoreCmd
+}
+
+// restoreHooks creates deleted hooks again, recording each as undoing its
+// delete so that it is not offered again. With noSecret, hooks that had a
+// secret are created without one.
+func restoreHooks(target hookTarget, deleted []deletedHook, noSecret bool) error {
+	for _, d := range deleted {
+		created, err := recreateHook(target, *d.entry.Before, d.change, noSecret)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Restored hook %d as %d ♻️\n", d.entry.HookId, created.Id)
+	}
+	return nil
+}
+
+func printDeletedHooks(w io.Writer, isTTY bool, width int, deleted []deletedHook) error {
+	tp := tableprinter.New(w, isTTY, width)
+	if isTTY {
+		for _, header := range []string{"ID", "CHANGE", "DELETED", "URL", "EVENTS"} {
+			tp.AddField(header)
+		}
+		tp.EndRow()
+	}
+	for _, d := range deleted {
+		tp.AddField(strconv.Itoa(d.entry.HookId))
+		tp.AddField(strconv.Itoa(d.change))
+		tp.AddField(d.entry.Time.Local().Format(time.RFC3339))
+		tp.AddField(d.entry.Before.Config.Url)
+		tp.AddField(strings.Join(d.entry.Before.Events, ", "))
+		tp.EndRow()
+	}
+	return tp.Render()
+}
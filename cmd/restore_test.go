@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_deletedHooksOf(t *testing.T) {
+	now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	deleted := func(target string, id int, at time.Time) historyEntry {
+		return historyEntry{Time: at, Host: "github.com", Scope: scopeRepository, Target: target, Action: "delete", HookId: id, Before: &Hook{Id: id}}
+	}
+	entries := []historyEntry{
+		deleted("lucasmelin/test-repo", 1, now.Add(-restoreWindow-time.Hour)),
+		deleted("lucasmelin/test-repo", 2, now.Add(-time.Hour)),
+		deleted("lucasmelin/other-repo", 3, now.Add(-time.Hour)),
+		{Time: now, Host: "github.com", Scope: scopeRepository, Target: "lucasmelin/test-repo", Action: "create", HookId: 4, After: &Hook{Id: 4}},
+		deleted("lucasmelin/test-repo", 4, now.Add(-time.Minute)),
+		deleted("lucasmelin/test-repo", 5, now),
+		{Time: now, Host: "github.com", Scope: scopeRepository, Target: "lucasmelin/test-repo", Action: "restore", HookId: 6, After: &Hook{Id: 6}, Undoes: 6},
+	}
+
+	repo := MockRepo{host: "github.com", name: "test-repo", owner: "lucasmelin"}
+	got := deletedHooksOf(repo, entries, now)
+	require.Len(t, got, 2)
+	assert.Equal(t, 5, got[0].change, "newest first")
+	assert.Equal(t, 4, got[0].entry.HookId)
+	assert.Equal(t, 2, got[1].change)
+	assert.Equal(t, 2, got[1].entry.HookId)
+}
+
+func Test_selectDeletedHooks(t *testing.T) {
+	deleted := []deletedHook{
+		{change: 3, entry: historyEntry{HookId: 1, Before: &Hook{Id: 1}}},
+		{change: 2, entry: historyEntry{HookId: 2, Before: &Hook{Id: 2}}},
+		{change: 1, entry: historyEntry{HookId: 1, Before: &Hook{Id: 1}}},
+	}
+	selected, err := selectDeletedHooks(deleted, []string{"1", "2"})
+	require.NoError(t, err)
+	require.Len(t, selected, 2)
+	assert.Equal(t, 3, selected[0].change, "latest delete of a hook")
+	assert.Equal(t, 2, selected[1].change)
+
+	_, err = selectDeletedHooks(deleted, []string{"9"})
+	assert.Error(t, err)
+}
+
+func Test_deleteHookKeepsHistory(t *testing.T) {
+	tests := []struct {
+		name       string
+		deleteCode int
+		wantErr    bool
+		wantKept   bool
+	}{
+		{
+			name:       "deleted",
+			deleteCode: 204,
+			wantKept:   true,
+		},
+		{
+			name:       "delete fails",
+			deleteCode: 403,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubConfig(t, testConfig())
+			t.Cleanup(gock.Off)
+			gock.New("https://api.github.com").
+				Get("repos/lucasmelin/test-repo/hooks/1").
+				Reply(200).
+				JSON(`{"id": 1, "name": "web", "active": true, "events": ["push"], "config": {"url": "https://example.com/webhook"}}`)
+			gock.New("https://api.github.com").
+				Delete("repos/lucasmelin/test-repo/hooks/1").
+				Reply(tt.deleteCode)
+
+			repo := MockRepo{host: "github.com", name: "test-repo", owner: "lucasmelin"}
+			err := deleteHook(repo, "1")
+			assert.Equal(t, tt.wantErr, err != nil, err)
+
+			entries, err := readHistory()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantKept, len(deletedHooksOf(repo, entries, time.Now())) == 1)
+			assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+		})
+	}
+}
+
+func Test_restoreHooks(t *testing.T) {
+	stubConfig(t, testConfig())
+	t.Cleanup(gock.Off)
+	repo := MockRepo{host: "github.com", name: "test-repo", owner: "lucasmelin"}
+	hook := Hook{
+		Id:        1,
+		Name:      "web",
+		Active:    true,
+		Events:    []string{"push"},
+		Config:    HookConfig{Url: "https://example.com/webhook", ContentType: "json"},
+		CreatedAt: "2019-06-03T00:57:16Z",
+	}
+	recordHistory(repo, "delete", &hook, nil)
+	gock.New("https://api.github.com").
+		Post("repos/lucasmelin/test-repo/hooks").
+		BodyString(`{"name":"web","active":true,"events":["push"],"config":{"url":"https://example.com/webhook","content_type":"json"}}`).
+		Reply(201).
+		JSON(`{"id": 2, "name": "web", "active": true, "events": ["push"], "config": {"url": "https://example.com/webhook"}}`)
+
+	entries, err := readHistory()
+	require.NoError(t, err)
+	require.NoError(t, restoreHooks(repo, deletedHooksOf(repo, entries, time.Now()), false))
+
+	entries, err = readHistory()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, 1, entries[1].Undoes)
+	assert.Empty(t, deletedHooksOf(repo, entries, time.Now()))
+	assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+}
+
+func Test_restoreHooksNoSecret(t *testing.T) {
+	stubConfig(t, testConfig())
+	t.Cleanup(gock.Off)
+	repo := MockRepo{host: "github.com", name: "test-repo", owner: "lucasmelin"}
+	hook := Hook{Id: 1, Name: "web", Active: true, Events: []string{"push"}, Config: HookConfig{Url: "https://example.com/webhook", Secret: "s3cret"}}
+	recordHistory(repo, "delete", &hook, nil)
+	entries, err := readHistory()
+	require.NoError(t, err)
+	deleted := deletedHooksOf(repo, entries, time.Now())
+
+	assert.Error(t, restoreHooks(repo, deleted, false), "the secret cannot be asked for outside a terminal")
+
+	gock.New("https://api.github.com").
+		Post("repos/lucasmelin/test-repo/hooks").
+		BodyString(`{"name":"web","active":true,"events":["push"],"config":{"url":"https://example.com/webhook"}}`).
+		Reply(201).
+		JSON(`{"id": 2, "name": "web", "active": true, "events": ["push"], "config": {"url": "https://example.com/webhook"}}`)
+	require.NoError(t, restoreHooks(repo, deleted, true))
+	assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+}
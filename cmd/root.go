@@ -6,6 +6,7 @@ import (
 
 	"github.com/cli/go-gh"
 	"github.com/cli/go-gh/pkg/repository"
+	"github.com/cli/go-gh/pkg/term"
 	"github.com/spf13/cobra"
 )
 
@@ -14,23 +15,39 @@ var rootCmd = &cobra.Command{
 	Short:             "Hook makes it easy to manage your repository webhooks.",
 	Long:              ``,
 	CompletionOptions: cobra.CompletionOptions{DisableDefaultCmd: true},
-	Run: func(cmd *cobra.Command, args []string) {
-		_ = cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !term.FromEnv().IsTerminalOutput() {
+			return cmd.Help()
+		}
+		return runDashboard(cmd)
 	},
 }
 
 func Execute() {
 	addCommandsToRoot()
 	rootCmd.PersistentFlags().String("repo", "", "Specify a repository. If omitted, uses the current repository.")
+	// The dashboard opened by the root command manages a target too.
+	addTargetFlags(rootCmd)
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
 }
 
 func addCommandsToRoot() {
+	rootCmd.AddCommand(NewCmdAudit())
 	rootCmd.AddCommand(NewCmdCreate())
 	rootCmd.AddCommand(NewCmdDelete())
+	rootCmd.AddCommand(NewCmdDisable())
+	rootCmd.AddCommand(NewCmdEdit())
+	rootCmd.AddCommand(NewCmdEnable())
+	rootCmd.AddCommand(NewCmdEvents())
+	rootCmd.AddCommand(NewCmdHealth())
+	rootCmd.AddCommand(NewCmdHistory())
+	rootCmd.AddCommand(NewCmdLint())
 	rootCmd.AddCommand(NewCmdList())
+	rootCmd.AddCommand(NewCmdRestore())
+	rootCmd.AddCommand(NewCmdRotateSecret())
+	rootCmd.AddCommand(NewCmdWatch())
 }
 
 func getRepo(cmd *cobra.Command) (repository.Repository, error) {
@@ -51,16 +68,24 @@ func getRepo(cmd *cobra.Command) (repository.Repository, error) {
 	return repo, nil
 }
 
-type Event struct {
-	Name string `json:"name"`
-}
-
 type Hook struct {
-	Id     int        `json:"id,omitempty"`
-	Name   string     `json:"name,omitempty"`
-	Active bool       `json:"active,omitempty"`
+	Id   int    `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	// Active is always sent, so that hooks can be created inactive.
+	Active bool       `json:"active"`
 	Events []string   `json:"events,omitempty"`
 	Config HookConfig `json:"config,omitempty"`
+	// The fields below are only set by the API.
+	CreatedAt    string        `json:"created_at,omitempty"`
+	UpdatedAt    string        `json:"updated_at,omitempty"`
+	LastResponse *HookResponse `json:"last_response,omitempty"`
+}
+
+// HookResponse is the result of the most recent delivery of a hook.
+type HookResponse struct {
+	Code    int    `json:"code,omitempty"`
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
 }
 
 type HookConfig struct {
@@ -68,66 +93,11 @@ type HookConfig struct {
 	ContentType string `json:"content_type,omitempty"`
 	InsecureSSL string `json:"insecure_ssl,omitempty"`
 	Secret      string `json:"secret,omitempty"`
+	// SecretFile is only used when reading hooks from a file, and is replaced
+	// by Secret before the hook is sent to the API.
+	SecretFile string `json:"secret_file,omitempty"`
 }
 
-// Some events are not available for repositories.
-// See: https://docs.github.com/developers/webhooks-and-events/webhooks/webhook-events-and-payloads#installation_repositories
-var knownEvents = []string{
-	"branch_protection_rule",
-	"check_run",
-	"check_suite",
-	"code_scanning_alert",
-	"commit_comment",
-	"create",
-	"delete",
-	"dependabot_alert",
-	"deploy_key",
-	"deployment",
-	"deployment_status",
-	"discussion",
-	"discussion_comment",
-	"fork",
-	// "github_app_authorization", Not available for repositories.
-	"gollum",
-	// "installation", Not available for repositories.
-	// "installation_repositories", Not available for repositories.
-	"issue_comment",
-	"issues",
-	"label",
-	// "marketplace_purchase", Not available for repositories.
-	"member",
-	// "membership", Not available for repositories.
-	// "merge_group", Not available for repositories.
-	"meta",
-	"milestone",
-	// "organization", Not available for repositories.
-	// "org_block", Not available for repositories.
-	"package",
-	"page_build",
-	"ping",
-	"project",
-	"project_card",
-	"project_column",
-	// "projects_v2_item", Not available for repositories.
-	"public",
-	"pull_request",
-	"pull_request_review",
-	"pull_request_review_comment",
-	"pull_request_review_thread",
-	"push",
-	"release",
-	// "repository_dispatch", Not available for repositories.
-	"repository",
-	"repository_import",
-	"repository_vulnerability_alert",
-	// "security_advisory", Not available for repositories.
-	// "sponsorship", Not available for repositories.
-	"star",
-	"status",
-	// "team", Not available for repositories.
-	"team_add",
-	"watch",
-	// "workflow_dispatch", Not available for repositories.
-	"workflow_job",
-	"workflow_run",
-}
+// knownEvents are the names of the events from the embedded catalog that can be
+// used in repository webhooks.
+var knownEvents = eventNames(catalogEvents(scopeRepository))
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/cli/go-gh/pkg/term"
+	"github.com/lucasmelin/gh-hook/tui"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdRotateSecret() *cobra.Command {
+	var rotateCmd = &cobra.Command{
+		Use:          "rotate-secret [<id>...]",
+		Short:        "Replace the secret of repository webhooks with a newly generated one.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := getTarget(cmd)
+			if err != nil {
+				return err
+			}
+
+			all, _ := cmd.Flags().GetBool("all")
+			secretOutput, _ := cmd.Flags().GetString("secret-output")
+
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			yes, _ := cmd.Flags().GetBool("yes")
+
+			currentHooks, err := getWebhooks(target)
+			if err != nil {
+				return fmt.Errorf("could not get webhooks: %w\n", err)
+			}
+			if len(currentHooks) == 0 {
+				fmt.Printf("%s has no webhooks\n", targetName(target))
+				return nil
+			}
+			hookIds := args
+			switch {
+			case len(hookIds) > 0:
+			case all:
+				hookIds = idsOfHooks(currentHooks)
+			default:
+				hooksToRotate, err := tui.ChooseMany("Which webhooks should get a new secret?", formatHookChoices(currentHooks))
+				if err != nil {
+					return fmt.Errorf("could not choose webhooks: %w", err)
+				}
+				hookIds = hookIdsFromChoices(hooksToRotate)
+			}
+			hooks, err := selectHooks(currentHooks, hookIds)
+			if err != nil {
+				return err
+			}
+			confirmed, err := confirmHooks(term.FromEnv(), "rotate the secret of", hooks, dryRun, yes)
+			if err != nil || !confirmed {
+				return err
+			}
+
+			rotated, err := rotateSecrets(target, hookIds)
+			if err != nil {
+				// The hooks rotated before the failure already use their new
+				// secret, which is lost unless it is written out now.
+				if outErr := outputSecrets(secretOutput, rotated); outErr != nil {
+					fmt.Fprint(os.Stderr, outErr)
+				}
+				return err
+			}
+			fmt.Printf("Rotated %d secrets 🔑\n", len(rotated))
+			return outputSecrets(secretOutput, rotated)
+		},
+	}
+	rotateCmd.Flags().Bool("all", false, "Rotate the secret of every webhook in the repository.")
+	rotateCmd.Flags().Bool("dry-run", false, "Show the webhooks whose secret would be rotated without changing them.")
+	rotateCmd.Flags().BoolP("yes", "y", false, "Rotate the secrets without asking for confirmation.")
+	rotateCmd.Flags().String("secret-output", "", "Append the new secrets to this file instead of printing them.")
+	addTargetFlags(rotateCmd)
+	return rotateCmd
+}
+
+// rotateSecrets generates a new secret for each hook and updates the hook
+// configuration with it. The returned hooks only have their ID and secret set.
+func rotateSecrets(target hookTarget, hookIds []string) ([]Hook, error) {
+	client, err := restClient(target)
+	if err != nil {
+		return nil, fmt.Errorf("error creating REST client: %w\n", err)
+	}
+	var rotated []Hook
+	for _, hookId := range hookIds {
+		id, err := strconv.Atoi(hookId)
+		if err != nil {
+			return rotated, fmt.Errorf("invalid hook ID %q: %w\n", hookId, err)
+		}
+		secret, err := generateSecret()
+		if err != nil {
+			return rotated, err
+		}
+		current, err := getWebhook(target, hookId)
+		if err != nil {
+			return rotated, fmt.Errorf("could not get webhook %s: %w\n", hookId, err)
+		}
+		updated := current
+		updated.Config.Secret = secret
+		var body interface{} = HookConfig{Secret: secret}
+		apiUrl := hookPath(target, hookId) + "/config"
+		if _, ok := target.(enterpriseTarget); ok {
+			// Global webhooks replace their whole configuration, so the
+			// current one is sent along with the new secret.
+			body = hookUpdate{Active: current.Active, Config: &updated.Config}
+			apiUrl = hookPath(target, hookId)
+		}
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return rotated, fmt.Errorf("could not convert config to JSON: %w\n", err)
+		}
+		if err := client.Patch(apiUrl, bytes.NewBuffer(jsonData), nil); err != nil {
+			return rotated, fmt.Errorf("could not update secret of hook %s: %w\n", hookId, err)
+		}
+		recordHistory(target, "rotate-secret", &current, &updated)
+		rotated = append(rotated, Hook{Id: id, Config: HookConfig{Secret: secret}})
+	}
+	return rotated, nil
+}
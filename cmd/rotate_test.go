@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/cli/go-gh/pkg/repository"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func Test_rotateSecrets(t *testing.T) {
+	tests := []struct {
+		name      string
+		repo      repository.Repository
+		hookIds   []string
+		httpMocks func()
+		wantIds   []int
+		wantErr   bool
+	}{
+		{
+			name: "rotate multiple hooks",
+			repo: MockRepo{
+				host:  "github.com",
+				name:  "test-repo",
+				owner: "lucasmelin",
+			},
+			hookIds: []string{"12345678", "4444333"},
+			httpMocks: func() {
+				gock.New("https://api.github.com").
+					Get("repos/lucasmelin/test-repo/hooks/12345678").
+					Reply(200).
+					JSON(`{"id": 12345678, "active": true, "events": ["push"], "config": {"url": "https://example.com/webhook"}}`)
+				gock.New("https://api.github.com").
+					Patch("repos/lucasmelin/test-repo/hooks/12345678/config").
+					BodyString(`{"secret":"[0-9a-f]{64}"}`).
+					Reply(200).
+					JSON(`{"content_type": "json", "insecure_ssl": "0", "url": "https://example.com/webhook"}`)
+				gock.New("https://api.github.com").
+					Get("repos/lucasmelin/test-repo/hooks/4444333").
+					Reply(200).
+					JSON(`{"id": 4444333, "active": true, "events": ["push"], "config": {"url": "https://example.com/webhook"}}`)
+				gock.New("https://api.github.com").
+					Patch("repos/lucasmelin/test-repo/hooks/4444333/config").
+					BodyString(`{"secret":"[0-9a-f]{64}"}`).
+					Reply(200).
+					JSON(`{"content_type": "json", "insecure_ssl": "0", "url": "https://example.com/webhook"}`)
+			},
+			wantIds: []int{12345678, 4444333},
+		},
+		{
+			name: "invalid hook ID",
+			repo: MockRepo{
+				host:  "github.com",
+				name:  "test-repo",
+				owner: "lucasmelin",
+			},
+			hookIds: []string{"not-a-hook"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubConfig(t, testConfig())
+			t.Cleanup(gock.Off)
+			if tt.httpMocks != nil {
+				tt.httpMocks()
+			}
+			got, err := rotateSecrets(tt.repo, tt.hookIds)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("rotateSecrets(%v, %v) error = %v, wantErr %v", tt.repo, tt.hookIds, err, tt.wantErr)
+			}
+			var gotIds []int
+			for _, hook := range got {
+				gotIds = append(gotIds, hook.Id)
+				assert.Len(t, hook.Config.Secret, 2*secretLength)
+			}
+			assert.Equal(t, tt.wantIds, gotIds)
+			assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+		})
+	}
+}
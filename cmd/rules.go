@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// severity is how serious a finding is, using the SARIF levels.
+type severity string
+
+const (
+	severityError   severity = "error"
+	severityWarning severity = "warning"
+	severityNote    severity = "note"
+)
+
+// severityOrder ranks severities from the most serious.
+var severityOrder = map[severity]int{severityError: 0, severityWarning: 1, severityNote: 2}
+
+// finding is a problem found with a hook.
+type finding struct {
+	Rule     string   `json:"rule"`
+	Severity severity `json:"severity"`
+	Repo     string   `json:"repo"`
+	HookId   int      `json:"hook_id"`
+	Url      string   `json:"url"`
+	Message  string   `json:"message"`
+}
+
+// ruleContext is what rules can look at besides the hook being checked.
+type ruleContext struct {
+	// Hooks are all the hooks of the repository, including the one checked.
+	Hooks []Hook
+	Now   time.Time
+}
+
+// rule is a check of a hook. Check returns a message describing the problem,
+// or an empty string when the hook passes.
+type rule struct {
+	ID          string
+	Description string
+	Severity    severity
+	Check       func(ctx ruleContext, hook Hook) string
+}
+
+// runRules checks every hook of a repository against rules.
+func runRules(rules []rule, repo string, hooks []Hook, now time.Time) []finding {
+	ctx := ruleContext{Hooks: hooks, Now: now}
+	var findings []finding
+	for _, hook := range hooks {
+		for _, r := range rules {
+			if message := r.Check(ctx, hook); message != "" {
+				findings = append(findings, finding{
+					Rule:     r.ID,
+					Severity: r.Severity,
+					Repo:     repo,
+					HookId:   hook.Id,
+					Url:      hook.Config.Url,
+					Message:  message,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// hasErrors reports whether any finding has the error severity.
+func hasErrors(findings []finding) bool {
+	for _, f := range findings {
+		if f.Severity == severityError {
+			return true
+		}
+	}
+	return false
+}
+
+// hookHost returns the lower-cased host name of the hook URL.
+func hookHost(hook Hook) string {
+	u, err := url.Parse(hook.Config.Url)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// normalizeUrl makes URLs that only differ in the case of their scheme and
+// host, or a trailing slash, equal.
+func normalizeUrl(hookUrl string) string {
+	u, err := url.Parse(hookUrl)
+	if err != nil {
+		return hookUrl
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u.String()
+}
+
+// sharedEvents returns the events that both event lists receive, taking "*"
+// into account.
+func sharedEvents(a, b []string) []string {
+	switch {
+	case contains(a, allEvents):
+		return b
+	case contains(b, allEvents):
+		return a
+	}
+	var shared []string
+	for _, event := range a {
+		if contains(b, event) {
+			shared = append(shared, event)
+		}
+	}
+	return shared
+}
+
+// mergeRules appends the extra rules that are not already in rules.
+func mergeRules(rules, extra []rule) []rule {
+	merged := append([]rule{}, rules...)
+	for _, r := range extra {
+		found := false
+		for _, existing := range rules {
+			if existing.ID == r.ID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, r)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_normalizeUrl(t *testing.T) {
+	assert.Equal(t, normalizeUrl("https://example.com/hook"), normalizeUrl("HTTPS://Example.com/hook/"))
+	assert.NotEqual(t, normalizeUrl("https://example.com/hook"), normalizeUrl("https://example.com/other"))
+}
+
+func Test_sharedEvents(t *testing.T) {
+	assert.Equal(t, []string{"push"}, sharedEvents([]string{"push", "issues"}, []string{"release", "push"}))
+	assert.Equal(t, []string{"release"}, sharedEvents([]string{"*"}, []string{"release"}))
+	assert.Empty(t, sharedEvents([]string{"push"}, []string{"release"}))
+}
+
+func Test_mergeRules(t *testing.T) {
+	merged := mergeRules([]rule{insecureSSLRule, httpUrlRule}, []rule{httpUrlRule, missingSecretRule})
+	var ids []string
+	for _, r := range merged {
+		ids = append(ids, r.ID)
+	}
+	assert.Equal(t, []string{"insecure-ssl", "http-url", "missing-secret"}, ids)
+}
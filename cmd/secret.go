@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// secretLength is the number of random bytes used for generated secrets.
+const secretLength = 32
+
+// secretEnvPattern matches secrets of the form ${env:NAME}, which are read from
+// the environment instead of being stored in the webhook file.
+var secretEnvPattern = regexp.MustCompile(`^\$\{env:([A-Za-z_][A-Za-z0-9_]*)\}$`)
+
+// secretSource describes where a secret should be read from when it is not
+// provided directly. At most one of the fields is expected to be set.
+type secretSource struct {
+	env    string
+	file   string
+	helper string
+}
+
+func (s secretSource) isSet() bool {
+	return s.env != "" || s.file != "" || s.helper != ""
+}
+
+// resolve reads the secret from the configured source. The hook is made
+// available to credential helpers through the GH_HOOK_URL variable.
+func (s secretSource) resolve(hook Hook) (string, error) {
+	switch {
+	case s.env != "":
+		return secretFromEnv(s.env)
+	case s.file != "":
+		return secretFromFile(s.file)
+	case s.helper != "":
+		return secretFromHelper(s.helper, hook)
+	}
+	return "", nil
+}
+
+// resolveSecret replaces indirect secret references in config, either
+// ${env:NAME} or secret_file, with the secret they point to. A relative
+// secret_file is relative to dir, the directory of the webhook file.
+func resolveSecret(config *HookConfig, dir string) error {
+	if config.SecretFile != "" {
+		if config.Secret != "" {
+			return fmt.Errorf("only one of secret and secret_file can be set")
+		}
+		path := config.SecretFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		secret, err := secretFromFile(path)
+		if err != nil {
+			return err
+		}
+		config.Secret = secret
+		config.SecretFile = ""
+		return nil
+	}
+	if match := secretEnvPattern.FindStringSubmatch(config.Secret); match != nil {
+		secret, err := secretFromEnv(match[1])
+		if err != nil {
+			return err
+		}
+		config.Secret = secret
+	}
+	return nil
+}
+
+func secretFromEnv(name string) (string, error) {
+	secret, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return secret, nil
+}
+
+func secretFromFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read secret file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// secretFromHelper runs an external credential helper, such as a vault CLI,
+// and uses its standard output as the secret. The command is run by the shell,
+// so that it can quote its arguments.
+func secretFromHelper(command string, hook Hook) (string, error) {
+	if strings.TrimSpace(command) == "" {
+		return "", fmt.Errorf("empty secret helper command")
+	}
+	helper := exec.Command("sh", "-c", command)
+	if runtime.GOOS == "windows" {
+		helper = exec.Command("cmd", "/C", command)
+	}
+	helper.Env = append(os.Environ(), "GH_HOOK_URL="+hook.Config.Url)
+	helper.Stderr = os.Stderr
+	out, err := helper.Output()
+	if err != nil {
+		return "", fmt.Errorf("secret helper failed: %w", err)
+	}
+	secret := strings.TrimRight(string(out), "\r\n")
+	if secret == "" {
+		return "", fmt.Errorf("secret helper returned an empty secret")
+	}
+	return secret, nil
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, secretLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("could not generate secret: %w\n", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// writeSecrets writes the secret of each hook as an env-style line, keyed by
+// the hook ID, so the output can be sourced or loaded as a dotenv file.
+func writeSecrets(w io.Writer, hooks []Hook) error {
+	for _, hook := range hooks {
+		if _, err := fmt.Fprintf(w, "HOOK_SECRET_%d=%s\n", hook.Id, hook.Config.Secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// outputSecrets prints the generated secrets once, or appends them to the file
+// at path when one is given.
+func outputSecrets(path string, hooks []Hook) error {
+	if len(hooks) == 0 {
+		return nil
+	}
+	if path == "" {
+		return writeSecrets(os.Stdout, hooks)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("could not open secret output file: %w\n", err)
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+	if err := writeSecrets(file, hooks); err != nil {
+		return fmt.Errorf("could not write secrets: %w\n", err)
+	}
+	fmt.Printf("Wrote %d secrets to %s\n", len(hooks), path)
+	return nil
+}
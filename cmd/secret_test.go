@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_generateSecret(t *testing.T) {
+	first, err := generateSecret()
+	if err != nil {
+		t.Fatalf("generateSecret() error = %v", err)
+	}
+	second, err := generateSecret()
+	if err != nil {
+		t.Fatalf("generateSecret() error = %v", err)
+	}
+	assert.Len(t, first, 2*secretLength)
+	assert.NotEqual(t, first, second)
+}
+
+func Test_writeSecrets(t *testing.T) {
+	hooks := []Hook{
+		{Id: 12345678, Config: HookConfig{Secret: "abc"}},
+		{Id: 4444333, Config: HookConfig{Secret: "def"}},
+	}
+	var out bytes.Buffer
+	if err := writeSecrets(&out, hooks); err != nil {
+		t.Fatalf("writeSecrets() error = %v", err)
+	}
+	assert.Equal(t, "HOOK_SECRET_12345678=abc\nHOOK_SECRET_4444333=def\n", out.String())
+}
+
+func Test_resolveSecret(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "secret")
+	if err := os.WriteFile(secretFile, []byte("fromfile\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOOK_SECRET", "fromenv")
+
+	tests := []struct {
+		name    string
+		config  HookConfig
+		want    HookConfig
+		wantErr bool
+	}{
+		{
+			name:   "plain secret",
+			config: HookConfig{Secret: "plain"},
+			want:   HookConfig{Secret: "plain"},
+		},
+		{
+			name:   "secret from environment",
+			config: HookConfig{Secret: "${env:HOOK_SECRET}"},
+			want:   HookConfig{Secret: "fromenv"},
+		},
+		{
+			name:    "missing environment variable",
+			config:  HookConfig{Secret: "${env:HOOK_SECRET_MISSING}"},
+			wantErr: true,
+		},
+		{
+			name:   "secret from file",
+			config: HookConfig{SecretFile: secretFile},
+			want:   HookConfig{Secret: "fromfile"},
+		},
+		{
+			name:   "secret from file relative to the webhook file",
+			config: HookConfig{SecretFile: "secret"},
+			want:   HookConfig{Secret: "fromfile"},
+		},
+		{
+			name:    "missing secret file",
+			config:  HookConfig{SecretFile: "missing"},
+			wantErr: true,
+		},
+		{
+			name:    "secret and secret file",
+			config:  HookConfig{Secret: "plain", SecretFile: secretFile},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := resolveSecret(&tt.config, dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveSecret() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, tt.config)
+			}
+		})
+	}
+}
+
+func Test_secretSource_resolve(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secretFile, []byte("fromfile\r\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOOK_SECRET", "fromenv")
+	hook := Hook{Config: HookConfig{Url: "https://example.com/hooks"}}
+
+	tests := []struct {
+		name    string
+		source  secretSource
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "no source",
+			source: secretSource{},
+			want:   "",
+		},
+		{
+			name:   "environment variable",
+			source: secretSource{env: "HOOK_SECRET"},
+			want:   "fromenv",
+		},
+		{
+			name:    "missing environment variable",
+			source:  secretSource{env: "HOOK_SECRET_MISSING"},
+			wantErr: true,
+		},
+		{
+			name:   "file",
+			source: secretSource{file: secretFile},
+			want:   "fromfile",
+		},
+		{
+			name:    "missing file",
+			source:  secretSource{file: secretFile + ".missing"},
+			wantErr: true,
+		},
+		{
+			name:   "helper",
+			source: secretSource{helper: "echo fromhelper"},
+			want:   "fromhelper",
+		},
+		{
+			name:   "helper with quoted arguments",
+			source: secretSource{helper: `printf '%s' "ci hooks/x"`},
+			want:   "ci hooks/x",
+		},
+		{
+			name:   "helper reading the webhook URL",
+			source: secretSource{helper: `echo "secret for $GH_HOOK_URL"`},
+			want:   "secret for https://example.com/hooks",
+		},
+		{
+			name:    "failing helper",
+			source:  secretSource{helper: "exit 1"},
+			wantErr: true,
+		},
+		{
+			name:    "helper with empty output",
+			source:  secretSource{helper: "true"},
+			wantErr: true,
+		},
+		{
+			name:    "empty helper command",
+			source:  secretSource{helper: " "},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.source.resolve(hook)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh"
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/cli/go-gh/pkg/auth"
+	"github.com/cli/go-gh/pkg/repository"
+	"github.com/spf13/cobra"
+)
+
+// hookTarget is where webhooks are managed: a repository, an organization, the
+// global webhooks of a GitHub Enterprise Server, or the webhook of a GitHub
+// App. Repositories are plain repository.Repository values.
+type hookTarget interface {
+	Host() string
+}
+
+// orgTarget is the webhooks of an organization.
+type orgTarget struct {
+	host string
+	org  string
+}
+
+func (t orgTarget) Host() string { return t.host }
+
+// enterpriseTarget is the global webhooks of a GitHub Enterprise Server, which
+// receive events from every organization on the server.
+type enterpriseTarget struct {
+	host string
+}
+
+func (t enterpriseTarget) Host() string { return t.host }
+
+// appTarget is the single webhook of a GitHub App. Its API authenticates as
+// the app, with a JWT signed by the private key of the app.
+type appTarget struct {
+	host  string
+	appId string
+	key   *rsa.PrivateKey
+}
+
+func (t appTarget) Host() string { return t.host }
+
+// targetKind returns the kind of webhooks of a target, which is also the scope
+// of the events they can receive.
+func targetKind(t hookTarget) string {
+	switch t.(type) {
+	case orgTarget:
+		return scopeOrganization
+	case enterpriseTarget:
+		return scopeEnterprise
+	case appTarget:
+		return scopeApp
+	default:
+		return scopeRepository
+	}
+}
+
+// targetName names a target in messages.
+func targetName(t hookTarget) string {
+	switch t := t.(type) {
+	case repository.Repository:
+		return t.Owner() + "/" + t.Name()
+	case orgTarget:
+		return t.org
+	case enterpriseTarget:
+		return t.host
+	default:
+		return "the GitHub App"
+	}
+}
+
+// hooksPath is the API path of the webhooks of a target.
+func hooksPath(t hookTarget) string {
+	switch t := t.(type) {
+	case repository.Repository:
+		return fmt.Sprintf("repos/%s/%s/hooks", t.Owner(), t.Name())
+	case orgTarget:
+		return fmt.Sprintf("orgs/%s/hooks", t.org)
+	case enterpriseTarget:
+		return "admin/hooks"
+	default:
+		return "app/hook"
+	}
+}
+
+// hookPath is the API path of a single webhook of a target. A GitHub App only
+// has one webhook, so its ID is ignored.
+func hookPath(t hookTarget, hookId string) string {
+	if _, ok := t.(appTarget); ok {
+		return hooksPath(t)
+	}
+	return hooksPath(t) + "/" + hookId
+}
+
+// unsupportedActions lists the actions the API does not offer for a kind of
+// target. Global webhooks have no deliveries, and the events and state of an
+// app webhook are part of the app settings.
+var unsupportedActions = map[string][]string{
+	scopeEnterprise: {"deliveries"},
+	scopeApp:        {"create", "delete", "enable", "disable", "events", "ping"},
+}
+
+// checkTarget returns an error when action is not supported for target.
+func checkTarget(t hookTarget, action string) error {
+	kind := targetKind(t)
+	if contains(unsupportedActions[kind], action) {
+		return fmt.Errorf("%s is not supported for %s webhooks\n", action, kind)
+	}
+	return nil
+}
+
+// restClient returns a client for the API of target.
+func restClient(t hookTarget) (api.RESTClient, error) {
+	opts := api.ClientOptions{Host: t.Host()}
+	if app, ok := t.(appTarget); ok {
+		token, err := appJWT(app.appId, app.key, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		opts.AuthToken = token
+		opts.Headers = map[string]string{"Authorization": "Bearer " + token}
+	}
+	return gh.RESTClient(&opts)
+}
+
+// getTarget returns the target selected by the --org, --enterprise and --app
+// flags, or else the repository given with --repo or the current one.
+// Organization, global and app webhooks are managed on the default host of gh,
+// which GH_HOST overrides.
+func getTarget(cmd *cobra.Command) (hookTarget, error) {
+	org, _ := cmd.Flags().GetString("org")
+	enterprise, _ := cmd.Flags().GetBool("enterprise")
+	app, _ := cmd.Flags().GetBool("app")
+	switch {
+	case org != "" && (enterprise || app), enterprise && app:
+		return nil, fmt.Errorf("only one of --org, --enterprise and --app can be given\n")
+	case org != "":
+		host, _ := auth.DefaultHost()
+		return orgTarget{host: host, org: org}, nil
+	case enterprise:
+		host, _ := auth.DefaultHost()
+		if !isEnterpriseHost(host) {
+			return nil, fmt.Errorf("global webhooks are only available on GitHub Enterprise Server, set GH_HOST to its hostname\n")
+		}
+		return enterpriseTarget{host: host}, nil
+	case app:
+		host, _ := auth.DefaultHost()
+		appId, _ := cmd.Flags().GetString("app-id")
+		keyFile, _ := cmd.Flags().GetString("app-key")
+		if appId == "" {
+			appId = os.Getenv("GH_HOOK_APP_ID")
+		}
+		if keyFile == "" {
+			keyFile = os.Getenv("GH_HOOK_APP_KEY")
+		}
+		if appId == "" || keyFile == "" {
+			return nil, fmt.Errorf("--app needs the ID and private key file of the app, given with --app-id and --app-key\n")
+		}
+		key, err := readAppKey(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		return appTarget{host: host, appId: appId, key: key}, nil
+	default:
+		return getRepo(cmd)
+	}
+}
+
+// addTargetFlags adds the flags selecting the webhooks a command manages. They
+// are only added to the commands that manage webhooks.
+func addTargetFlags(cmd *cobra.Command) {
+	cmd.Flags().String("org", "", "Manage the webhooks of this organization instead of repository webhooks.")
+	cmd.Flags().Bool("enterprise", false, "Manage the global webhooks of a GitHub Enterprise Server instead of repository webhooks.")
+	cmd.Flags().Bool("app", false, "Manage the webhook of a GitHub App instead of repository webhooks.")
+	cmd.Flags().String("app-id", "", "The ID of the GitHub App, for --app. Defaults to GH_HOOK_APP_ID.")
+	cmd.Flags().String("app-key", "", "The private key file of the GitHub App, for --app. Defaults to GH_HOOK_APP_KEY.")
+}
+
+func readAppKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read app private key: %w\n", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("app private key %s is not a PEM file\n", path)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse app private key: %w\n", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("app private key %s is not an RSA key\n", path)
+	}
+	return key, nil
+}
+
+// appJWT returns a JWT authenticating as a GitHub App for the next few
+// minutes. It is issued a minute in the past to allow for clock drift.
+// See: https://docs.github.com/apps/creating-github-apps/authenticating-with-a-github-app/generating-a-json-web-token-jwt-for-a-github-app
+func appJWT(appId string, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": appId,
+	})
+	if err != nil {
+		return "", err
+	}
+	encoding := base64.RawURLEncoding
+	unsigned := encoding.EncodeToString(header) + "." + encoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("could not sign app token: %w\n", err)
+	}
+	return strings.Join([]string{unsigned, encoding.EncodeToString(signature)}, "."), nil
+}
@@ -0,0 +1,324 @@
+package cmd
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func testAppKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+func Test_hookPath(t *testing.T) {
+	tests := []struct {
+		name   string
+		target hookTarget
+		want   string
+	}{
+		{
+			name:   "repository",
+			target: MockRepo{host: "github.com", owner: "octocat", name: "Hello-World"},
+			want:   "repos/octocat/Hello-World/hooks/1",
+		},
+		{
+			name:   "organization",
+			target: orgTarget{host: "github.com", org: "octo-org"},
+			want:   "orgs/octo-org/hooks/1",
+		},
+		{
+			name:   "enterprise",
+			target: enterpriseTarget{host: "ghe.example.com"},
+			want:   "admin/hooks/1",
+		},
+		{
+			name:   "app",
+			target: appTarget{host: "github.com"},
+			want:   "app/hook",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, hookPath(tt.target, "1"))
+		})
+	}
+}
+
+func Test_checkTarget(t *testing.T) {
+	repo := MockRepo{host: "github.com", owner: "octocat", name: "Hello-World"}
+	assert.NoError(t, checkTarget(repo, "create"))
+	assert.NoError(t, checkTarget(repo, "deliveries"))
+	assert.NoError(t, checkTarget(enterpriseTarget{}, "create"))
+	assert.EqualError(t, checkTarget(enterpriseTarget{}, "deliveries"), "deliveries is not supported for enterprise webhooks\n")
+	assert.EqualError(t, checkTarget(appTarget{}, "delete"), "delete is not supported for app webhooks\n")
+	assert.NoError(t, checkTarget(appTarget{}, "deliveries"))
+}
+
+func Test_appJWT(t *testing.T) {
+	key := testAppKey(t)
+	now := time.Unix(1700000000, 0)
+	token, err := appJWT("12345", key, now)
+	require.NoError(t, err)
+
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+	claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal(claims, &got))
+	assert.Equal(t, "12345", got["iss"])
+	assert.Equal(t, float64(now.Unix()-60), got["iat"])
+	assert.Equal(t, float64(now.Unix()+540), got["exp"])
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+}
+
+func Test_readAppKey(t *testing.T) {
+	key := testAppKey(t)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{
+			name: "PKCS1",
+			data: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		},
+		{
+			name: "PKCS8",
+			data: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}),
+		},
+		{
+			name:    "not PEM",
+			data:    []byte("not a key"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.pem")
+			require.NoError(t, os.WriteFile(path, tt.data, 0600))
+			got, err := readAppKey(path)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, key.Equal(got))
+		})
+	}
+}
+
+func Test_getWebhooksApp(t *testing.T) {
+	stubConfig(t, testConfig())
+	t.Cleanup(gock.Off)
+	gock.New("https://api.github.com").
+		Get("app/hook/config").
+		MatchHeader("Authorization", "^Bearer ").
+		Reply(200).
+		JSON(`{"content_type": "json", "insecure_ssl": "0", "url": "https://example.com/webhook"}`)
+
+	got, err := getWebhooks(appTarget{host: "github.com", appId: "12345", key: testAppKey(t)})
+	require.NoError(t, err)
+	assert.Equal(t, []Hook{
+		{
+			Name:   "app",
+			Active: true,
+			Config: HookConfig{Url: "https://example.com/webhook", ContentType: "json", InsecureSSL: "0"},
+		},
+	}, got)
+	assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+}
+
+func Test_updateHookEnterprise(t *testing.T) {
+	stubConfig(t, ghesTestConfig())
+	t.Cleanup(gock.Off)
+	gock.New("https://ghe.example.com").
+		Get("api/v3/admin/hooks/1").
+		Reply(200).
+		JSON(`{"id": 1, "active": false, "events": ["user"], "config": {"url": "https://example.com/webhook"}}`)
+	gock.New("https://ghe.example.com").
+		Patch("api/v3/admin/hooks/1").
+		BodyString(`{"active":true,"events":["organization"],"config":{"url":"https://example.com/webhook","content_type":"json"}}`).
+		Reply(200).
+		JSON(`{"id": 1}`)
+
+	err := updateHook(enterpriseTarget{host: "ghe.example.com"}, "1", Hook{
+		Active: true,
+		Events: []string{"organization"},
+		Config: HookConfig{Url: "https://example.com/webhook", ContentType: "json"},
+	})
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+}
+
+func Test_updateHookEnterpriseKeepsSecret(t *testing.T) {
+	current := `{"id": 1, "active": true, "events": ["user"], "config": {"url": "https://example.com/webhook", "content_type": "json", "insecure_ssl": "0", "secret": "********"}}`
+	tests := []struct {
+		name    string
+		data    Hook
+		body    string
+		wantErr bool
+	}{
+		{
+			name: "events changed, configuration left out",
+			data: Hook{
+				Active: true,
+				Events: []string{"organization"},
+				Config: HookConfig{Url: "https://example.com/webhook", ContentType: "json", InsecureSSL: "0"},
+			},
+			body: `{"active":true,"events":["organization"]}`,
+		},
+		{
+			name: "configuration changed with a new secret",
+			data: Hook{
+				Active: true,
+				Events: []string{"user"},
+				Config: HookConfig{Url: "https://example.com/other", ContentType: "json", InsecureSSL: "0", Secret: "s3cret"},
+			},
+			body: `{"active":true,"events":["user"],"config":{"url":"https://example.com/other","content_type":"json","insecure_ssl":"0","secret":"s3cret"}}`,
+		},
+		{
+			name: "configuration changed without the secret",
+			data: Hook{
+				Active: true,
+				Events: []string{"user"},
+				Config: HookConfig{Url: "https://example.com/other", ContentType: "json", InsecureSSL: "0"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubConfig(t, ghesTestConfig())
+			t.Cleanup(gock.Off)
+			gock.New("https://ghe.example.com").
+				Get("api/v3/admin/hooks/1").
+				Reply(200).
+				JSON(current)
+			if tt.body != "" {
+				gock.New("https://ghe.example.com").
+					Patch("api/v3/admin/hooks/1").
+					BodyString(tt.body).
+					Reply(200).
+					JSON(`{"id": 1}`)
+			}
+
+			err := updateHook(enterpriseTarget{host: "ghe.example.com"}, "1", tt.data)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			if tt.wantErr {
+				assert.Contains(t, err.Error(), "enter the secret again")
+			}
+			assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+		})
+	}
+}
+
+func Test_updateHookEventsEnterprise(t *testing.T) {
+	stubConfig(t, ghesTestConfig())
+	t.Cleanup(gock.Off)
+	gock.New("https://ghe.example.com").
+		Patch("api/v3/admin/hooks/1").
+		BodyString(`{"active":true,"events":["organization","repository"]}`).
+		Reply(200).
+		JSON(`{"id": 1, "active": true, "events": ["organization", "repository"]}`)
+
+	current := Hook{Id: 1, Active: true, Events: []string{"organization", "user"}}
+	got, err := updateHookEvents(enterpriseTarget{host: "ghe.example.com"}, current, []string{"repository"}, []string{"user"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"organization", "repository"}, got.Events)
+	assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+}
+
+func Test_updateHookApp(t *testing.T) {
+	stubConfig(t, testConfig())
+	t.Cleanup(gock.Off)
+	app := appTarget{host: "github.com", appId: "12345", key: testAppKey(t)}
+	config := `{"content_type": "json", "url": "https://example.com/webhook"}`
+
+	gock.New("https://api.github.com").
+		Get("app/hook/config").
+		Reply(200).
+		JSON(config)
+	err := updateHook(app, "", Hook{Active: true, Events: []string{"push"}, Config: HookConfig{Url: "https://example.com/webhook"}})
+	assert.Error(t, err, "events of an app webhook cannot be changed")
+
+	gock.New("https://api.github.com").
+		Get("app/hook/config").
+		Reply(200).
+		JSON(config)
+	gock.New("https://api.github.com").
+		Patch("app/hook/config").
+		BodyString(`{"url":"https://example.com/new","content_type":"json"}`).
+		Reply(200).
+		JSON(`{}`)
+	err = updateHook(app, "", Hook{Active: true, Config: HookConfig{Url: "https://example.com/new", ContentType: "json"}})
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone(), printPendingMocks(gock.Pending()))
+}
+
+func Test_getTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    hookTarget
+		wantErr bool
+	}{
+		{
+			name: "organization",
+			args: []string{"--org", "octo-org"},
+			want: orgTarget{host: "github.com", org: "octo-org"},
+		},
+		{
+			name:    "organization and app",
+			args:    []string{"--org", "octo-org", "--app"},
+			wantErr: true,
+		},
+		{
+			name:    "enterprise and app",
+			args:    []string{"--enterprise", "--app"},
+			wantErr: true,
+		},
+		{
+			name:    "enterprise on GitHub.com",
+			args:    []string{"--enterprise"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubConfig(t, testConfig())
+			cmd := &cobra.Command{}
+			addTargetFlags(cmd)
+			require.NoError(t, cmd.ParseFlags(tt.args))
+			got, err := getTarget(cmd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getTarget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
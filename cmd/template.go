@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cli/go-gh/pkg/config"
+	"github.com/lucasmelin/gh-hook/tui"
+)
+
+// builtinTemplates are presets for common webhook integrations. User templates
+// with the same name take precedence.
+var builtinTemplates = map[string]Hook{
+	"argocd": {
+		Events: []string{"push"},
+		Config: HookConfig{ContentType: "json", InsecureSSL: "0"},
+	},
+	"jenkins": {
+		Events: []string{"push", "pull_request"},
+		Config: HookConfig{ContentType: "json", InsecureSSL: "0"},
+	},
+	"slack": {
+		Events: []string{
+			"deployment_status",
+			"issue_comment",
+			"issues",
+			"pull_request",
+			"pull_request_review",
+			"push",
+			"release",
+		},
+		Config: HookConfig{ContentType: "json", InsecureSSL: "0"},
+	},
+}
+
+// templateDir is where user templates are stored, one JSON file per template
+// using the same format as the create --file flag.
+func templateDir() string {
+	return filepath.Join(config.ConfigDir(), "gh-hook", "templates")
+}
+
+// loadTemplates returns the built-in templates merged with the user templates.
+func loadTemplates() (map[string]Hook, error) {
+	templates := map[string]Hook{}
+	for name, tmpl := range builtinTemplates {
+		templates[name] = tmpl
+	}
+	entries, err := os.ReadDir(templateDir())
+	if errors.Is(err, fs.ErrNotExist) {
+		return templates, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read template directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		file, err := os.Open(filepath.Join(templateDir(), entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("could not open template: %w", err)
+		}
+		var tmpl Hook
+		err = json.NewDecoder(file).Decode(&tmpl)
+		_ = file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("could not parse template %s: %w", entry.Name(), err)
+		}
+		templates[strings.TrimSuffix(entry.Name(), ".json")] = tmpl
+	}
+	return templates, nil
+}
+
+func templateNames(templates map[string]Hook) []string {
+	var names []string
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func getTemplate(name string) (Hook, error) {
+	templates, err := loadTemplates()
+	if err != nil {
+		return Hook{}, err
+	}
+	tmpl, ok := templates[name]
+	if !ok {
+		return Hook{}, fmt.Errorf("unknown template %q, available templates: %s", name, strings.Join(templateNames(templates), ", "))
+	}
+	return tmpl, nil
+}
+
+// applyTemplate fills in the fields of hook that are not already set with the
+// values from the template.
+func applyTemplate(hook Hook, tmpl Hook) Hook {
+	if len(hook.Events) == 0 {
+		hook.Events = tmpl.Events
+	}
+	if hook.Config.Url == "" {
+		hook.Config.Url = tmpl.Config.Url
+	}
+	if hook.Config.ContentType == "" {
+		hook.Config.ContentType = tmpl.Config.ContentType
+	}
+	if hook.Config.InsecureSSL == "" {
+		hook.Config.InsecureSSL = tmpl.Config.InsecureSSL
+	}
+	return hook
+}
+
+// chooseTemplate prompts for one of the available templates. Choosing "none"
+// returns an empty template.
+func chooseTemplate() (Hook, error) {
+	templates, err := loadTemplates()
+	if err != nil {
+		return Hook{}, err
+	}
+	choice, err := tui.ChooseOne("Template", append([]string{"none"}, templateNames(templates)...))
+	if err != nil {
+		return Hook{}, fmt.Errorf("could not choose template: %w\n", err)
+	}
+	return templates[choice], nil
+}
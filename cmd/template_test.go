@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getTemplate(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("GH_CONFIG_DIR", configDir)
+	dir := filepath.Join(configDir, "gh-hook", "templates")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	err := os.WriteFile(filepath.Join(dir, "relay.json"), []byte(`{"events": ["release"], "config": {"content_type": "form"}}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		templateName string
+		want         Hook
+		wantErr      bool
+	}{
+		{
+			name:         "built-in template",
+			templateName: "jenkins",
+			want:         builtinTemplates["jenkins"],
+		},
+		{
+			name:         "user template",
+			templateName: "relay",
+			want: Hook{
+				Events: []string{"release"},
+				Config: HookConfig{ContentType: "form"},
+			},
+		},
+		{
+			name:         "unknown template",
+			templateName: "missing",
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getTemplate(tt.templateName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getTemplate(%v) error = %v, wantErr %v", tt.templateName, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_applyTemplate(t *testing.T) {
+	tests := []struct {
+		name string
+		hook Hook
+		tmpl Hook
+		want Hook
+	}{
+		{
+			name: "fills in missing fields",
+			hook: Hook{Config: HookConfig{Url: "https://example.com"}},
+			tmpl: builtinTemplates["jenkins"],
+			want: Hook{
+				Events: []string{"push", "pull_request"},
+				Config: HookConfig{Url: "https://example.com", ContentType: "json", InsecureSSL: "0"},
+			},
+		},
+		{
+			name: "keeps existing fields",
+			hook: Hook{
+				Events: []string{"release"},
+				Config: HookConfig{Url: "https://example.com", ContentType: "form"},
+			},
+			tmpl: builtinTemplates["jenkins"],
+			want: Hook{
+				Events: []string{"release"},
+				Config: HookConfig{Url: "https://example.com", ContentType: "form", InsecureSSL: "0"},
+			},
+		},
+		{
+			name: "empty template",
+			hook: Hook{Config: HookConfig{Url: "https://example.com"}},
+			want: Hook{Config: HookConfig{Url: "https://example.com"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, applyTemplate(tt.hook, tt.tmpl))
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// watchPageSize is the number of deliveries fetched on each poll, which
+	// is usually enough to catch up without following the cursor.
+	watchPageSize = 25
+	// watchMaxPages limits how far back a poll follows the cursor after a
+	// burst of deliveries.
+	watchMaxPages = 4
+)
+
+var (
+	successStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	failureStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+// deliveryPager fetches a page of deliveries starting at a cursor, returning
+// the cursor of the next page.
+type deliveryPager func(cursor string) ([]Delivery, string, error)
+
+func NewCmdWatch() *cobra.Command {
+	var watchCmd = &cobra.Command{
+		Use:          "watch [<id>]",
+		Short:        "Stream the deliveries of a repository webhook as they happen.",
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := getTarget(cmd)
+			if err != nil {
+				return err
+			}
+			if err := checkTarget(target, "deliveries"); err != nil {
+				return err
+			}
+			interval, _ := cmd.Flags().GetDuration("interval")
+			bell, _ := cmd.Flags().GetBool("bell")
+			if interval < time.Second {
+				return fmt.Errorf("--interval must be at least 1s\n")
+			}
+			if err := checkFeature(target.Host(), featureDeliveries); err != nil {
+				return err
+			}
+			// A GitHub App has a single webhook, so it needs no ID.
+			hookId := "app"
+			if len(args) > 0 {
+				hookId = args[0]
+			} else if _, ok := target.(appTarget); !ok {
+				return fmt.Errorf("a webhook ID is required\n")
+			}
+			pager := func(cursor string) ([]Delivery, string, error) {
+				return getDeliveriesPage(target, hookId, watchPageSize, cursor)
+			}
+
+			// Start from the latest delivery, so that only new ones are shown.
+			latest, _, err := pager("")
+			if err != nil {
+				return fmt.Errorf("could not get deliveries: %w\n", err)
+			}
+			lastSeen := 0
+			if len(latest) > 0 {
+				lastSeen = latest[0].Id
+			}
+			fmt.Fprintf(os.Stderr, "Watching deliveries of hook %s, press ctrl+c to stop\n", hookId)
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+				}
+				deliveries, err := pollDeliveries(pager, lastSeen)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "could not get deliveries: %v\n", err)
+					continue
+				}
+				for _, d := range deliveries {
+					printWatchedDelivery(os.Stdout, d, bell)
+					lastSeen = d.Id
+				}
+			}
+		},
+	}
+	watchCmd.Flags().Duration("interval", 5*time.Second, "How often to check for new deliveries.")
+	watchCmd.Flags().Bool("bell", false, "Ring the terminal bell when a delivery fails.")
+	addTargetFlags(watchCmd)
+	return watchCmd
+}
+
+// pollDeliveries returns the deliveries newer than lastSeen, oldest first. It
+// follows the cursor to older pages only while every delivery on a page is
+// new.
+func pollDeliveries(pager deliveryPager, lastSeen int) ([]Delivery, error) {
+	var newest []Delivery
+	cursor := ""
+	for page := 0; page < watchMaxPages; page++ {
+		deliveries, next, err := pager(cursor)
+		if err != nil {
+			return nil, err
+		}
+		caughtUp := false
+		for _, d := range deliveries {
+			if d.Id <= lastSeen {
+				caughtUp = true
+				break
+			}
+			newest = append(newest, d)
+		}
+		if caughtUp || next == "" {
+			break
+		}
+		cursor = next
+	}
+	for i, j := 0, len(newest)-1; i < j; i, j = i+1, j-1 {
+		newest[i], newest[j] = newest[j], newest[i]
+	}
+	return newest, nil
+}
+
+func printWatchedDelivery(w io.Writer, d Delivery, bell bool) {
+	style := successStyle
+	if !d.succeeded() {
+		style = failureStyle
+	}
+	fmt.Fprintln(w, style.Render(formatDelivery(d)))
+	if bell && !d.succeeded() {
+		fmt.Fprint(os.Stderr, "\a")
+	}
+}
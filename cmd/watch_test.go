@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_pollDeliveries(t *testing.T) {
+	// Three pages of two deliveries, newest first.
+	pages := map[string]struct {
+		deliveries []Delivery
+		next       string
+	}{
+		"":   {deliveries: []Delivery{{Id: 6}, {Id: 5}}, next: "c1"},
+		"c1": {deliveries: []Delivery{{Id: 4}, {Id: 3}}, next: "c2"},
+		"c2": {deliveries: []Delivery{{Id: 2}, {Id: 1}}},
+	}
+	var requested []string
+	pager := func(cursor string) ([]Delivery, string, error) {
+		requested = append(requested, cursor)
+		page := pages[cursor]
+		return page.deliveries, page.next, nil
+	}
+	ids := func(deliveries []Delivery) []int {
+		var ids []int
+		for _, d := range deliveries {
+			ids = append(ids, d.Id)
+		}
+		return ids
+	}
+
+	t.Run("nothing new", func(t *testing.T) {
+		requested = nil
+		got, err := pollDeliveries(pager, 6)
+		assert.NoError(t, err)
+		assert.Empty(t, got)
+		assert.Equal(t, []string{""}, requested)
+	})
+
+	t.Run("new deliveries on the first page", func(t *testing.T) {
+		requested = nil
+		got, err := pollDeliveries(pager, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{6}, ids(got))
+		assert.Equal(t, []string{""}, requested)
+	})
+
+	t.Run("follows the cursor until caught up", func(t *testing.T) {
+		requested = nil
+		got, err := pollDeliveries(pager, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{3, 4, 5, 6}, ids(got))
+		assert.Equal(t, []string{"", "c1", "c2"}, requested)
+	})
+}
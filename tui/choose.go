@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/paginator"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/mattn/go-runewidth"
@@ -17,6 +18,7 @@ import (
 var (
 	subduedStyle     = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#847A85", Dark: "#979797"})
 	verySubduedStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#DDDADA", Dark: "#3C3C3C"})
+	matchStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Underline(true)
 )
 
 // keyMap defines a set of keybindings. To work for help it must satisfy
@@ -30,6 +32,8 @@ type keyMap struct {
 	Select      key.Binding
 	SelectAll   key.Binding
 	DeselectAll key.Binding
+	Filter      key.Binding
+	ClearFilter key.Binding
 	Help        key.Binding
 	Quit        key.Binding
 }
@@ -37,7 +41,14 @@ type keyMap struct {
 // ShortHelp returns keybindings to be shown in the mini help view. It's part
 // of the key.Map interface.
 func (k keyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Help, k.Quit}
+	return []key.Binding{k.Filter, k.Help, k.Quit}
+}
+
+// bound reports whether msg is one of the keybindings, rather than text that
+// starts a filter.
+func (k keyMap) bound(msg tea.KeyMsg) bool {
+	return key.Matches(msg, k.Up, k.Down, k.Left, k.Right, k.Enter, k.Select,
+		k.SelectAll, k.DeselectAll, k.Filter, k.ClearFilter, k.Help, k.Quit)
 }
 
 // FullHelp returns keybindings for the expanded help view. It's part of the
@@ -46,6 +57,7 @@ func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Left, k.Right},
 		{k.Select, k.SelectAll, k.DeselectAll},
+		{k.Filter, k.ClearFilter},
 		{k.Help, k.Quit},
 	}
 }
@@ -81,6 +93,14 @@ var keys = keyMap{
 		key.WithKeys("A"),
 		key.WithHelp("A", "deselect all"),
 	),
+	Filter: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/ or type", "filter"),
+	),
+	ClearFilter: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "clear filter"),
+	),
 	Help: key.NewBinding(
 		key.WithKeys("?"),
 		key.WithHelp("?", "toggle help"),
@@ -91,8 +111,77 @@ var keys = keyMap{
 	),
 }
 
-func ChooseMany(title string, options []string) ([]string, error) {
-	choice, err := choose(title, options, 0)
+// ChooseOption customizes the behaviour of ChooseMany and ChooseOne.
+type ChooseOption func(*chooseModel)
+
+// WithDescriptions shows a subdued description next to each option that has
+// one, keyed by the option text.
+func WithDescriptions(descriptions map[string]string) ChooseOption {
+	return func(m *chooseModel) {
+		m.descriptions = descriptions
+	}
+}
+
+// WithSelected starts with the options matching values selected, such as the
+// current values of a setting being edited.
+func WithSelected(values []string) ChooseOption {
+	return func(m *chooseModel) {
+		m.initial = values
+	}
+}
+
+// WithCursor starts with the cursor on the option matching value, instead of
+// the first option.
+func WithCursor(value string) ChooseOption {
+	return func(m *chooseModel) {
+		m.initialCursor = value
+	}
+}
+
+// WithLimit sets the maximum number of options that can be selected when
+// choosing many.
+func WithLimit(limit int) ChooseOption {
+	return func(m *chooseModel) {
+		if limit > 0 {
+			m.limit = limit
+		}
+	}
+}
+
+// WithHeight sets the number of options shown on each page.
+func WithHeight(height int) ChooseOption {
+	return func(m *chooseModel) {
+		if height > 0 {
+			m.height = height
+		}
+	}
+}
+
+// Selection is the result of Choose.
+type Selection struct {
+	// Values are the selected options, in the order they were selected.
+	Values []string
+	// Changed is set when the selected options differ from the ones
+	// preselected with WithSelected, regardless of their order. Values given
+	// to WithSelected that are not options are ignored.
+	Changed bool
+}
+
+// Choose lets the user choose any number of options, up to the limit set with
+// WithLimit, and reports whether the selection changed. Unlike ChooseMany,
+// choosing nothing is not an error when options were preselected, so that a
+// selection can be cleared.
+func Choose(title string, options []string, opts ...ChooseOption) (Selection, error) {
+	m, err := run(newChooseModel(title, options, 0, opts...))
+	if err != nil {
+		return Selection{}, err
+	}
+	values := m.selection()
+	return Selection{Values: values, Changed: !sameValues(values, m.preselected)}, nil
+}
+
+func ChooseMany(title string, options []string, opts ...ChooseOption) ([]string, error) {
+	choice, err := choose(title, options, 0, opts...)
 	if err != nil {
 		return choice, err
 	}
@@ -102,8 +191,8 @@ func ChooseMany(title string, options []string) ([]string, error) {
 	return choice, err
 }
 
-func ChooseOne(title string, options []string) (string, error) {
-	choice, err := choose(title, options, 1)
+func ChooseOne(title string, options []string, opts ...ChooseOption) (string, error) {
+	choice, err := choose(title, options, 1, opts...)
 	if err != nil {
 		return "", err
 	}
@@ -113,7 +202,49 @@ func ChooseOne(title string, options []string) (string, error) {
 	return choice[0], err
 }
 
-func choose(title string, options []string, limit int) ([]string, error) {
+func choose(title string, options []string, limit int, opts ...ChooseOption) ([]string, error) {
+	m, err := run(newChooseModel(title, options, limit, opts...))
+	if err != nil {
+		return []string{}, err
+	}
+	return m.selection(), nil
+}
+
+// run shows the prompt until an option is chosen or it is cancelled.
+func run(model chooseModel) (chooseModel, error) {
+	tm, err := tea.NewProgram(model, tea.WithOutput(os.Stderr)).Run()
+
+	if err != nil {
+		return model, fmt.Errorf("failed to start tea program: %w", err)
+	}
+
+	m := tm.(chooseModel)
+	if m.cancelled {
+		return m, fmt.Errorf("cancelled")
+	}
+	return m, nil
+}
+
+// sameValues reports whether a and b have the same values, in any order.
+func sameValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := map[string]int{}
+	for _, value := range a {
+		counts[value]++
+	}
+	for _, value := range b {
+		counts[value]--
+		if counts[value] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func newChooseModel(title string, options []string, limit int, opts ...ChooseOption) chooseModel {
+	multiple := limit != 1
 	if limit == 0 {
 		limit = len(options)
 	}
@@ -132,7 +263,12 @@ func choose(title string, options []string, limit int) ([]string, error) {
 		items[i] = item{text: option, selected: false, order: i}
 	}
 
-	tm, err := tea.NewProgram(chooseModel{
+	filter := textinput.New()
+	filter.Prompt = "/ "
+	filter.Placeholder = "Type to filter..."
+	filter.PromptStyle = subduedStyle
+
+	model := chooseModel{
 		title:             title,
 		index:             0,
 		currentOrder:      0,
@@ -142,39 +278,85 @@ func choose(title string, options []string, limit int) ([]string, error) {
 		unselectedPrefix:  " ",
 		cursorPrefix:      "",
 		items:             items,
+		filter:            filter,
 		limit:             limit,
+		multiple:          multiple,
 		keys:              keys,
 		help:              help.New(),
 		paginator:         pager,
 		cursorStyle:       lipgloss.NewStyle().Foreground(lipgloss.Color("212")),
 		selectedItemStyle: lipgloss.NewStyle().Foreground(lipgloss.Color("212")),
 		numSelected:       0,
-	}, tea.WithOutput(os.Stderr)).Run()
-
-	if err != nil {
-		return []string{}, fmt.Errorf("failed to start tea program: %w", err)
 	}
+	for _, opt := range opts {
+		opt(&model)
+	}
+	// The options are applied first, as the height and limit affect the
+	// pages and the preselection.
+	model.paginator.PerPage = model.height
+	model.preselect(model.initial)
+	model.refilter()
+	if model.initialCursor != "" {
+		model.moveTo(model.initialCursor)
+	}
+	return model
+}
 
-	m := tm.(chooseModel)
-	if m.cancelled {
-		return []string{}, fmt.Errorf("cancelled")
+// moveTo moves the cursor to the visible item with the given text.
+func (m *chooseModel) moveTo(text string) {
+	for i, index := range m.visible {
+		if m.items[index].text == text {
+			m.index = i
+			m.paginator.Page = i / m.height
+			return
+		}
 	}
+}
 
-	if limit > 1 {
-		sort.Slice(m.items, func(i, j int) bool {
-			return m.items[i].order < m.items[j].order
+// selection returns the selected items, in the order they were selected when
+// more than one item can be chosen.
+func (m chooseModel) selection() []string {
+	items := append([]item{}, m.items...)
+	if m.multiple {
+		sort.Slice(items, func(i, j int) bool {
+			return items[i].order < items[j].order
 		})
 	}
 
 	var results []string
 
-	for _, item := range m.items {
+	for _, item := range items {
 		if item.selected {
 			results = append(results, item.text)
 		}
 	}
 
-	return results, nil
+	return results
+}
+
+// preselect selects the items with the given texts, and remembers the ones
+// that were selected. Values that are not options are ignored.
+func (m *chooseModel) preselect(values []string) {
+	for _, value := range values {
+		for i := range m.items {
+			if m.items[i].text == value && !m.items[i].selected && m.numSelected < m.limit {
+				m.items[i].selected = true
+				m.items[i].order = m.currentOrder
+				m.numSelected++
+				m.currentOrder++
+				m.preselected = append(m.preselected, value)
+			}
+		}
+	}
+}
+
+// done ends the prompt, unless the model is embedded in another one, which
+// checks quitting instead.
+func (m chooseModel) done() tea.Cmd {
+	if m.embedded {
+		return nil
+	}
+	return tea.Quit
 }
 
 type item struct {
@@ -191,6 +373,8 @@ type chooseModel struct {
 	unselectedPrefix string
 	cursorPrefix     string
 	items            []item
+	descriptions     map[string]string
+	width            int
 	quitting         bool
 	index            int
 	limit            int
@@ -200,6 +384,29 @@ type chooseModel struct {
 	help             help.Model
 	paginator        paginator.Model
 	cancelled        bool
+	// multiple is set when more than one item can be chosen, even if there
+	// is only one option.
+	multiple bool
+	// hint is shown below the list until the next key is pressed.
+	hint string
+	// initial and initialCursor are the options given with WithSelected and
+	// WithCursor.
+	initial       []string
+	initialCursor string
+	// preselected are the initial values that were selected, leaving out
+	// the ones that are not options or are over the limit.
+	preselected []string
+	// embedded is set when the model is part of another model, such as a
+	// form, and must not quit the program.
+	embedded bool
+
+	// filtering
+	filter    textinput.Model
+	filtering bool
+	// visible are the indices of the items matching the filter, in the order
+	// they are shown, and matches the matched rune positions of each.
+	visible []int
+	matches map[int][]int
 
 	// styles
 	cursorStyle       lipgloss.Style
@@ -209,17 +416,67 @@ type chooseModel struct {
 
 func (m chooseModel) Init() tea.Cmd { return nil }
 
+// refilter updates the visible items to the ones matching the filter. The
+// selection of items is kept, even when they are filtered out.
+func (m *chooseModel) refilter() {
+	pattern := m.filter.Value()
+	if pattern == "" {
+		m.visible = make([]int, len(m.items))
+		for i := range m.items {
+			m.visible[i] = i
+		}
+		m.matches = nil
+	} else {
+		texts := make([]string, len(m.items))
+		for i, item := range m.items {
+			texts[i] = item.text
+		}
+		m.visible, m.matches = fuzzyFilter(pattern, texts)
+	}
+	m.index = 0
+	m.paginator.Page = 0
+	m.paginator.SetTotalPages(max(len(m.visible), 1))
+}
+
+func (m *chooseModel) toggle(i int) {
+	if m.items[i].selected {
+		m.items[i].selected = false
+		m.numSelected--
+	} else if m.numSelected < m.limit {
+		m.items[i].selected = true
+		m.items[i].order = m.currentOrder
+		m.numSelected++
+		m.currentOrder++
+	}
+}
+
 func (m chooseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
+		m.width = msg.Width
 		return m, nil
 
 	case tea.KeyMsg:
-		start, end := m.paginator.GetSliceBounds(len(m.items))
+		m.hint = ""
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
+		// Typing text that is not a keybinding starts filtering with it, so
+		// that "/" is only needed for filters starting with a bound key.
+		if msg.Type == tea.KeyRunes && !m.keys.bound(msg) {
+			m.filtering = true
+			focus := m.filter.Focus()
+			updated, cmd := m.updateFilter(msg)
+			return updated, tea.Batch(focus, cmd)
+		}
+		start, end := m.paginator.GetSliceBounds(len(m.visible))
 		switch {
 		case key.Matches(msg, m.keys.Down):
+			if len(m.visible) == 0 {
+				break
+			}
 			m.index++
-			if m.index >= len(m.items) {
+			if m.index >= len(m.visible) {
 				m.index = 0
 				m.paginator.Page = 0
 			}
@@ -227,25 +484,30 @@ func (m chooseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.paginator.NextPage()
 			}
 		case key.Matches(msg, m.keys.Up):
+			if len(m.visible) == 0 {
+				break
+			}
 			m.index--
 			if m.index < 0 {
-				m.index = len(m.items) - 1
+				m.index = len(m.visible) - 1
 				m.paginator.Page = m.paginator.TotalPages - 1
 			}
 			if m.index < start {
 				m.paginator.PrevPage()
 			}
 		case key.Matches(msg, m.keys.Right):
-			m.index = clamp(m.index+m.height, 0, len(m.items)-1)
+			m.index = clamp(m.index+m.height, 0, max(len(m.visible)-1, 0))
 			m.paginator.NextPage()
 		case key.Matches(msg, m.keys.Left):
-			m.index = clamp(m.index-m.height, 0, len(m.items)-1)
+			m.index = clamp(m.index-m.height, 0, max(len(m.visible)-1, 0))
 			m.paginator.PrevPage()
 		case key.Matches(msg, m.keys.SelectAll):
-			if m.limit <= 1 {
+			if !m.multiple {
 				break
 			}
-			for i := range m.items {
+			// Only the visible items are selected, so that filtering and
+			// selecting all works like a pattern.
+			for _, i := range m.visible {
 				if m.numSelected >= m.limit {
 					break // do not exceed given limit
 				}
@@ -258,7 +520,7 @@ func (m chooseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.currentOrder++
 			}
 		case key.Matches(msg, m.keys.DeselectAll):
-			if m.limit <= 1 {
+			if !m.multiple {
 				break
 			}
 			for i := range m.items {
@@ -267,33 +529,24 @@ func (m chooseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			m.numSelected = 0
 			m.currentOrder = 0
+		case key.Matches(msg, m.keys.Filter):
+			m.filtering = true
+			return m, m.filter.Focus()
+		case key.Matches(msg, m.keys.ClearFilter) && m.filter.Value() != "":
+			m.filter.SetValue("")
+			m.refilter()
+			return m, nil
 		case key.Matches(msg, m.keys.Quit):
 			m.cancelled = true
 			m.quitting = true
-			return m, tea.Quit
+			return m, m.done()
 		case key.Matches(msg, m.keys.Select):
-			if m.limit == 1 {
+			if !m.multiple || len(m.visible) == 0 {
 				break // no op
 			}
-
-			if m.items[m.index].selected {
-				m.items[m.index].selected = false
-				m.numSelected--
-			} else if m.numSelected < m.limit {
-				m.items[m.index].selected = true
-				m.items[m.index].order = m.currentOrder
-				m.numSelected++
-				m.currentOrder++
-			}
+			m.toggle(m.visible[m.index])
 		case key.Matches(msg, m.keys.Enter):
-			m.quitting = true
-			// If the user hasn't selected any items in a multi-select.
-			// Then we select the item that they have pressed enter on. If they
-			// have selected items, then we simply return them.
-			if m.numSelected < 1 {
-				m.items[m.index].selected = true
-			}
-			return m, tea.Quit
+			return m.confirm()
 		case key.Matches(msg, m.keys.Help):
 			m.help.ShowAll = !m.help.ShowAll
 		}
@@ -304,6 +557,66 @@ func (m chooseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// updateFilter handles keys while the filter is being typed. Only keys that
+// cannot be part of the filter are used for navigation.
+func (m chooseModel) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.cancelled = true
+		m.quitting = true
+		return m, m.done()
+	case "esc":
+		m.filtering = false
+		m.filter.Blur()
+		return m, nil
+	case "enter":
+		return m.confirm()
+	case "down":
+		if len(m.visible) > 0 {
+			m.index = (m.index + 1) % len(m.visible)
+			m.paginator.Page = m.index / m.height
+		}
+		return m, nil
+	case "up":
+		if len(m.visible) > 0 {
+			m.index = (m.index - 1 + len(m.visible)) % len(m.visible)
+			m.paginator.Page = m.index / m.height
+		}
+		return m, nil
+	case "tab":
+		if m.multiple && len(m.visible) > 0 {
+			m.toggle(m.visible[m.index])
+		}
+		return m, nil
+	}
+	previous := m.filter.Value()
+	var cmd tea.Cmd
+	m.filter, cmd = m.filter.Update(msg)
+	if m.filter.Value() != previous {
+		m.refilter()
+	}
+	return m, cmd
+}
+
+func (m chooseModel) confirm() (tea.Model, tea.Cmd) {
+	// In a multi-select, items must be selected explicitly, so that enter
+	// never acts on the item under the cursor by accident. Only a preselection
+	// may be cleared completely. In a single select, the item under the cursor
+	// is the choice.
+	if m.multiple && m.numSelected < 1 && len(m.preselected) == 0 {
+		m.hint = "Nothing selected, press space to select an item"
+		return m, nil
+	}
+	if !m.multiple {
+		if len(m.visible) == 0 {
+			return m, nil
+		}
+		m.items[m.visible[m.index]].selected = true
+	}
+	m.quitting = true
+	return m, m.done()
+}
+
 func (m chooseModel) View() string {
 	if m.quitting {
 		return ""
@@ -312,38 +625,88 @@ func (m chooseModel) View() string {
 	var s strings.Builder
 
 	s.WriteString(m.cursorStyle.Render(m.title) + "\n")
+	if m.filtering || m.filter.Value() != "" {
+		s.WriteString(m.filter.View() + "\n")
+	}
+	if len(m.visible) == 0 {
+		s.WriteString(subduedStyle.Render("  No matches") + "\n")
+	}
 
-	start, end := m.paginator.GetSliceBounds(len(m.items))
-	for i, item := range m.items[start:end] {
+	start, end := m.paginator.GetSliceBounds(len(m.visible))
+	for i, index := range m.visible[start:end] {
+		item := m.items[index]
 		if i == m.index%m.height {
 			s.WriteString(m.cursorStyle.Render(m.cursor))
 		} else {
 			s.WriteString(strings.Repeat(" ", runewidth.StringWidth(m.cursor)))
 		}
 
+		var prefix string
+		var style lipgloss.Style
 		if item.selected {
-			s.WriteString(m.selectedItemStyle.Render(m.selectedPrefix + item.text))
+			prefix, style = m.selectedPrefix, m.selectedItemStyle
 		} else if i == m.index%m.height {
-			s.WriteString(m.cursorStyle.Render(m.cursorPrefix + item.text))
+			prefix, style = m.cursorPrefix, m.cursorStyle
 		} else {
-			s.WriteString(m.itemStyle.Render(m.unselectedPrefix + item.text))
+			prefix, style = m.unselectedPrefix, m.itemStyle
+		}
+		s.WriteString(style.Render(prefix))
+		s.WriteString(renderMatches(item.text, m.matches[index], style))
+		if description := m.descriptions[item.text]; description != "" {
+			description = "  " + description
+			if m.width > 0 {
+				available := m.width - runewidth.StringWidth(m.cursor) - runewidth.StringWidth(prefix+item.text)
+				description = runewidth.Truncate(description, max(available, 0), "…")
+			}
+			s.WriteString(subduedStyle.Render(description))
 		}
 		if i != m.height {
 			s.WriteRune('\n')
 		}
 	}
+	if m.hint != "" {
+		s.WriteString(errorStyle.Render(m.hint) + "\n")
+	}
+	if m.multiple && m.limit < len(m.items) {
+		s.WriteString(subduedStyle.Render(fmt.Sprintf("%d/%d selected", m.numSelected, m.limit)) + "\n")
+	}
 
-	if m.paginator.TotalPages <= 1 {
-		return s.String()
+	if m.paginator.TotalPages > 1 {
+		s.WriteString(strings.Repeat("\n", m.height-m.paginator.ItemsOnPage(len(m.visible))+1))
+		s.WriteString("  " + m.paginator.View() + "\n")
 	}
+	s.WriteString(m.help.View(m.keys))
 
-	s.WriteString(strings.Repeat("\n", m.height-m.paginator.ItemsOnPage(len(m.items))+1))
-	s.WriteString("  " + m.paginator.View())
-	s.WriteString("\n" + m.help.View(m.keys))
+	return s.String()
+}
 
+// renderMatches renders text in style, highlighting the runes at positions.
+func renderMatches(text string, positions []int, style lipgloss.Style) string {
+	if len(positions) == 0 {
+		return style.Render(text)
+	}
+	matched := map[int]bool{}
+	for _, p := range positions {
+		matched[p] = true
+	}
+	var s strings.Builder
+	for i, r := range []rune(text) {
+		if matched[i] {
+			s.WriteString(matchStyle.Render(string(r)))
+		} else {
+			s.WriteString(style.Render(string(r)))
+		}
+	}
 	return s.String()
 }
 
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 func clamp(x, min, max int) int {
 	if x < min {
 		return min
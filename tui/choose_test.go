@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_chooseModel_confirm(t *testing.T) {
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+
+	t.Run("enter with nothing selected keeps choosing many", func(t *testing.T) {
+		m := newChooseModel("Delete", []string{"1", "2"}, 0)
+		updated, _ := m.Update(enter)
+		m = updated.(chooseModel)
+		assert.False(t, m.quitting)
+		assert.Empty(t, m.selection())
+		assert.NotEmpty(t, m.hint)
+	})
+
+	t.Run("a single option must still be selected when choosing many", func(t *testing.T) {
+		m := newChooseModel("Delete", []string{"1"}, 0)
+		updated, _ := m.Update(enter)
+		assert.False(t, updated.(chooseModel).quitting)
+		updated, _ = updated.Update(space)
+		updated, _ = updated.Update(enter)
+		m = updated.(chooseModel)
+		assert.True(t, m.quitting)
+		assert.Equal(t, []string{"1"}, m.selection())
+	})
+
+	t.Run("enter chooses the item under the cursor when choosing one", func(t *testing.T) {
+		m := newChooseModel("Edit", []string{"1", "2"}, 1)
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		updated, _ = updated.Update(enter)
+		m = updated.(chooseModel)
+		assert.True(t, m.quitting)
+		assert.Equal(t, []string{"2"}, m.selection())
+	})
+}
+
+func Test_newChooseModel_options(t *testing.T) {
+	options := []string{"a", "b", "c", "d", "e"}
+
+	t.Run("preselected values", func(t *testing.T) {
+		m := newChooseModel("Events", options, 0, WithSelected([]string{"c", "a", "z"}))
+		assert.Equal(t, []string{"c", "a"}, m.selection())
+		assert.Equal(t, 2, m.numSelected)
+		assert.True(t, sameValues(m.selection(), m.preselected), "values that are not options do not count as a change")
+	})
+
+	t.Run("values over the limit are not preselected", func(t *testing.T) {
+		m := newChooseModel("Events", options, 0, WithSelected([]string{"a", "b", "c"}), WithLimit(2))
+		assert.Equal(t, []string{"a", "b"}, m.preselected)
+	})
+
+	t.Run("cursor on a value", func(t *testing.T) {
+		m := newChooseModel("Events", options, 0, WithCursor("d"), WithHeight(2))
+		assert.Equal(t, 3, m.index)
+		assert.Equal(t, 1, m.paginator.Page)
+		assert.Equal(t, 3, m.paginator.TotalPages)
+	})
+
+	t.Run("limit applies to the preselection regardless of order", func(t *testing.T) {
+		m := newChooseModel("Events", options, 0, WithSelected([]string{"a", "b", "c"}), WithLimit(2))
+		assert.Equal(t, []string{"a", "b"}, m.selection())
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+		assert.Equal(t, 2, updated.(chooseModel).numSelected)
+	})
+
+	t.Run("a preselection can be cleared", func(t *testing.T) {
+		m := newChooseModel("Events", options, 0, WithSelected([]string{"a"}))
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+		updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = updated.(chooseModel)
+		assert.True(t, m.quitting)
+		assert.Empty(t, m.selection())
+	})
+}
+
+func Test_chooseModel_typeToFilter(t *testing.T) {
+	typed := func(m tea.Model, text string) chooseModel {
+		for _, r := range text {
+			m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+		return m.(chooseModel)
+	}
+	options := []string{"push", "pull_request", "release"}
+
+	t.Run("typing starts filtering", func(t *testing.T) {
+		m := typed(newChooseModel("Events", options, 0), "rel")
+		assert.True(t, m.filtering)
+		assert.Equal(t, "rel", m.filter.Value())
+		assert.Equal(t, []int{2}, m.visible)
+	})
+
+	t.Run("keybindings still apply before filtering", func(t *testing.T) {
+		m := typed(newChooseModel("Events", options, 0), "j")
+		assert.False(t, m.filtering)
+		assert.Equal(t, 1, m.index)
+	})
+
+	t.Run("slash starts a filter with a bound key", func(t *testing.T) {
+		m := typed(newChooseModel("Events", options, 0), "/a")
+		assert.True(t, m.filtering)
+		assert.Equal(t, "a", m.filter.Value())
+	})
+}
+
+func Test_sameValues(t *testing.T) {
+	assert.True(t, sameValues([]string{"a", "b"}, []string{"b", "a"}))
+	assert.True(t, sameValues(nil, []string{}))
+	assert.False(t, sameValues([]string{"a"}, []string{"a", "b"}))
+	assert.False(t, sameValues([]string{"a", "a"}, []string{"a", "b"}))
+}
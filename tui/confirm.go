@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Confirm asks a yes or no question, defaulting to no.
+func Confirm(prompt string) (bool, error) {
+	tm, err := tea.NewProgram(confirmModel{prompt: prompt}, tea.WithOutput(os.Stderr)).Run()
+	if err != nil {
+		return false, fmt.Errorf("failed to start tea program: %w", err)
+	}
+	m := tm.(confirmModel)
+	if m.cancelled {
+		return false, fmt.Errorf("cancelled")
+	}
+	return m.confirmed, nil
+}
+
+type confirmModel struct {
+	prompt    string
+	confirmed bool
+	quitting  bool
+	cancelled bool
+}
+
+func (m confirmModel) Init() tea.Cmd { return nil }
+
+func (m confirmModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			m.cancelled = true
+			m.quitting = true
+			return m, tea.Quit
+		case "left", "right", "tab", "h", "l":
+			m.confirmed = !m.confirmed
+		case "y", "Y":
+			m.confirmed = true
+			m.quitting = true
+			return m, tea.Quit
+		case "n", "N":
+			m.confirmed = false
+			m.quitting = true
+			return m, tea.Quit
+		case "enter":
+			m.quitting = true
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+func (m confirmModel) View() string {
+	if m.quitting {
+		return ""
+	}
+	yes, no := "  Yes  ", "  No  "
+	buttonStyle := lipgloss.NewStyle().Background(lipgloss.Color("237"))
+	if m.confirmed {
+		yes = highlightStyle.Copy().Reverse(true).Render(yes)
+		no = buttonStyle.Render(no)
+	} else {
+		yes = buttonStyle.Render(yes)
+		no = highlightStyle.Copy().Reverse(true).Render(no)
+	}
+	return m.prompt + "\n\n" + yes + " " + no + "\n\n" + subduedStyle.Render("←/→ toggle • y/n • enter submit")
+}
@@ -0,0 +1,405 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+var (
+	paneStyle        = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("240")).Padding(0, 1)
+	focusedPaneStyle = paneStyle.Copy().BorderForeground(lipgloss.Color("212"))
+	highlightStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("212"))
+	errorStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+)
+
+// DashboardEntry is a row shown in one of the dashboard lists.
+type DashboardEntry struct {
+	ID    string
+	Title string
+}
+
+// DashboardAction is an action that can be triggered on the selected entry.
+type DashboardAction struct {
+	Name string
+	Key  key.Binding
+	// Exit closes the dashboard and returns the action to the caller instead
+	// of running it, which is needed for actions that show their own prompts.
+	Exit bool
+	// Confirm requires the key to be pressed twice before the action runs.
+	Confirm bool
+	// NeedsSubEntry only allows the action when an entry of the details list,
+	// such as a delivery, is selected.
+	NeedsSubEntry bool
+	// Run performs the action on the selected entry and, if any, the selected
+	// entry of the details list. The returned message is shown in the status
+	// line.
+	Run func(id, subID string) (string, error)
+}
+
+// DashboardSource provides the data shown in the dashboard.
+type DashboardSource struct {
+	// Load returns the entries shown in the list on the left.
+	Load func() ([]DashboardEntry, error)
+	// Details returns the details of an entry, along with the entries of the
+	// list shown below them, such as recent deliveries.
+	Details func(id string) (string, []DashboardEntry, error)
+	// DetailsTitle is the heading of the details list.
+	DetailsTitle string
+	Actions      []DashboardAction
+}
+
+// DashboardResult is the action that closed the dashboard, if any.
+type DashboardResult struct {
+	Action string
+	ID     string
+}
+
+// Dashboard opens a full-screen view with a list of entries on the left and the
+// details of the selected entry on the right.
+func Dashboard(title string, source DashboardSource) (DashboardResult, error) {
+	tm, err := tea.NewProgram(dashboardModel{
+		title:   title,
+		source:  source,
+		keys:    newDashboardKeyMap(source.Actions),
+		help:    help.New(),
+		details: map[string]dashboardDetails{},
+		loading: true,
+	}, tea.WithAltScreen(), tea.WithOutput(os.Stderr)).Run()
+	if err != nil {
+		return DashboardResult{}, fmt.Errorf("failed to start tea program: %w", err)
+	}
+	return tm.(dashboardModel).result, nil
+}
+
+type dashboardKeyMap struct {
+	Up      key.Binding
+	Down    key.Binding
+	Focus   key.Binding
+	Refresh key.Binding
+	Help    key.Binding
+	Quit    key.Binding
+	Actions []key.Binding
+}
+
+func newDashboardKeyMap(actions []DashboardAction) dashboardKeyMap {
+	km := dashboardKeyMap{
+		Up:      keys.Up,
+		Down:    keys.Down,
+		Focus:   key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "switch pane")),
+		Refresh: key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "refresh")),
+		Help:    keys.Help,
+		Quit:    keys.Quit,
+	}
+	for _, action := range actions {
+		km.Actions = append(km.Actions, action.Key)
+	}
+	return km
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k dashboardKeyMap) ShortHelp() []key.Binding {
+	return append(append([]key.Binding{}, k.Actions...), k.Help, k.Quit)
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k dashboardKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Focus, k.Refresh},
+		k.Actions,
+		{k.Help, k.Quit},
+	}
+}
+
+type dashboardDetails struct {
+	text    string
+	entries []DashboardEntry
+	err     error
+}
+
+type entriesMsg struct {
+	entries []DashboardEntry
+	err     error
+}
+
+type detailsMsg struct {
+	id      string
+	details dashboardDetails
+}
+
+type actionMsg struct {
+	status string
+	err    error
+}
+
+type dashboardModel struct {
+	title   string
+	source  DashboardSource
+	keys    dashboardKeyMap
+	help    help.Model
+	width   int
+	height  int
+	entries []DashboardEntry
+	index   int
+	// subIndex is the selected entry of the details list.
+	subIndex     int
+	detailsFocus bool
+	details      map[string]dashboardDetails
+	loading      bool
+	pending      string
+	status       string
+	err          error
+	result       DashboardResult
+}
+
+func (m dashboardModel) Init() tea.Cmd {
+	return m.load()
+}
+
+func (m dashboardModel) load() tea.Cmd {
+	return func() tea.Msg {
+		entries, err := m.source.Load()
+		return entriesMsg{entries: entries, err: err}
+	}
+}
+
+func (m dashboardModel) loadDetails(id string) tea.Cmd {
+	if m.source.Details == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		text, entries, err := m.source.Details(id)
+		return detailsMsg{id: id, details: dashboardDetails{text: text, entries: entries, err: err}}
+	}
+}
+
+func (m dashboardModel) selected() (DashboardEntry, bool) {
+	if m.index < 0 || m.index >= len(m.entries) {
+		return DashboardEntry{}, false
+	}
+	return m.entries[m.index], true
+}
+
+func (m dashboardModel) selectedSub() (DashboardEntry, bool) {
+	entry, ok := m.selected()
+	if !ok {
+		return DashboardEntry{}, false
+	}
+	subEntries := m.details[entry.ID].entries
+	if m.subIndex < 0 || m.subIndex >= len(subEntries) {
+		return DashboardEntry{}, false
+	}
+	return subEntries[m.subIndex], true
+}
+
+// selectionChanged loads the details of the newly selected entry unless they
+// are already known.
+func (m dashboardModel) selectionChanged() (dashboardModel, tea.Cmd) {
+	m.subIndex = 0
+	entry, ok := m.selected()
+	if !ok {
+		return m, nil
+	}
+	if _, known := m.details[entry.ID]; known {
+		return m, nil
+	}
+	return m, m.loadDetails(entry.ID)
+}
+
+func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.help.Width = msg.Width
+		return m, nil
+
+	case entriesMsg:
+		m.loading = false
+		m.err = msg.err
+		m.entries = msg.entries
+		m.details = map[string]dashboardDetails{}
+		m.index = clamp(m.index, 0, max(len(m.entries)-1, 0))
+		return m.selectionChanged()
+
+	case detailsMsg:
+		m.details[msg.id] = msg.details
+		return m, nil
+
+	case actionMsg:
+		m.status = msg.status
+		m.err = msg.err
+		m.loading = true
+		return m, m.load()
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m dashboardModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	pending := m.pending
+	m.pending = ""
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Help):
+		m.help.ShowAll = !m.help.ShowAll
+		return m, nil
+	case key.Matches(msg, m.keys.Focus):
+		m.detailsFocus = !m.detailsFocus
+		return m, nil
+	case key.Matches(msg, m.keys.Refresh):
+		m.loading = true
+		return m, m.load()
+	case key.Matches(msg, m.keys.Down):
+		if m.detailsFocus {
+			if entry, ok := m.selected(); ok {
+				m.subIndex = clamp(m.subIndex+1, 0, max(len(m.details[entry.ID].entries)-1, 0))
+			}
+			return m, nil
+		}
+		if len(m.entries) > 0 {
+			m.index = (m.index + 1) % len(m.entries)
+		}
+		return m.selectionChanged()
+	case key.Matches(msg, m.keys.Up):
+		if m.detailsFocus {
+			m.subIndex = max(m.subIndex-1, 0)
+			return m, nil
+		}
+		if len(m.entries) > 0 {
+			m.index = (m.index - 1 + len(m.entries)) % len(m.entries)
+		}
+		return m.selectionChanged()
+	}
+
+	for _, action := range m.source.Actions {
+		if !key.Matches(msg, action.Key) {
+			continue
+		}
+		entry, ok := m.selected()
+		if !ok && !action.Exit {
+			return m, nil
+		}
+		if action.Exit {
+			m.result = DashboardResult{Action: action.Name, ID: entry.ID}
+			return m, tea.Quit
+		}
+		sub, hasSub := m.selectedSub()
+		if action.NeedsSubEntry && (!hasSub || !m.detailsFocus) {
+			m.status = fmt.Sprintf("Select an entry in %s to %s it", strings.ToLower(m.source.DetailsTitle), action.Name)
+			return m, nil
+		}
+		if action.Confirm && pending != action.Name {
+			m.pending = action.Name
+			m.status = fmt.Sprintf("Press %s again to %s %s", action.Key.Help().Key, action.Name, entry.ID)
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Running %s on %s…", action.Name, entry.ID)
+		run := action.Run
+		return m, func() tea.Msg {
+			status, err := run(entry.ID, sub.ID)
+			return actionMsg{status: status, err: err}
+		}
+	}
+	return m, nil
+}
+
+func (m dashboardModel) View() string {
+	if m.width == 0 {
+		return ""
+	}
+	helpView := m.help.View(m.keys)
+	status := subduedStyle.Render(m.status)
+	if m.err != nil {
+		status = errorStyle.Render(m.err.Error())
+	}
+	header := highlightStyle.Render(m.title)
+
+	// Leave room for the header, status line, help and pane borders.
+	paneHeight := max(m.height-lipgloss.Height(helpView)-6, 3)
+	leftWidth := max(m.width/3, 20)
+	rightWidth := max(m.width-leftWidth-4, 20)
+
+	left := m.listView(leftWidth-4, paneHeight)
+	right := m.detailsView(rightWidth-4, paneHeight)
+	leftStyle, rightStyle := focusedPaneStyle, paneStyle
+	if m.detailsFocus {
+		leftStyle, rightStyle = paneStyle, focusedPaneStyle
+	}
+	panes := lipgloss.JoinHorizontal(lipgloss.Top,
+		leftStyle.Width(leftWidth-2).Height(paneHeight).Render(left),
+		rightStyle.Width(rightWidth-2).Height(paneHeight).Render(right),
+	)
+	return lipgloss.JoinVertical(lipgloss.Left, header, panes, status, helpView)
+}
+
+func (m dashboardModel) listView(width, height int) string {
+	if m.loading && len(m.entries) == 0 {
+		return subduedStyle.Render("Loading…")
+	}
+	if len(m.entries) == 0 {
+		return subduedStyle.Render("Nothing here yet")
+	}
+	return renderEntries(m.entries, m.index, !m.detailsFocus, width, height)
+}
+
+func (m dashboardModel) detailsView(width, height int) string {
+	entry, ok := m.selected()
+	if !ok {
+		return ""
+	}
+	details, known := m.details[entry.ID]
+	if !known {
+		return subduedStyle.Render("Loading…")
+	}
+	if details.err != nil {
+		return errorStyle.Render(details.err.Error())
+	}
+	var s strings.Builder
+	s.WriteString(details.text)
+	s.WriteString("\n\n" + highlightStyle.Render(m.source.DetailsTitle) + "\n")
+	if len(details.entries) == 0 {
+		s.WriteString(subduedStyle.Render("None"))
+		return s.String()
+	}
+	remaining := max(height-lipgloss.Height(s.String()), 1)
+	subIndex := clamp(m.subIndex, 0, len(details.entries)-1)
+	s.WriteString(renderEntries(details.entries, subIndex, m.detailsFocus, width, remaining))
+	return s.String()
+}
+
+// renderEntries renders the window of entries around the cursor that fits in
+// height.
+func renderEntries(entries []DashboardEntry, index int, focused bool, width, height int) string {
+	start := 0
+	if index >= height {
+		start = index - height + 1
+	}
+	end := start + height
+	if end > len(entries) {
+		end = len(entries)
+	}
+	var lines []string
+	for i := start; i < end; i++ {
+		text := runewidth.Truncate(entries[i].Title, max(width-2, 1), "…")
+		if i == index && focused {
+			lines = append(lines, highlightStyle.Render("> "+text))
+		} else if i == index {
+			lines = append(lines, "> "+text)
+		} else {
+			lines = append(lines, "  "+text)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
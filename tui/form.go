@@ -0,0 +1,359 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// FieldKind is the kind of input of a form field.
+type FieldKind int
+
+const (
+	// TextField is a single line of text.
+	TextField FieldKind = iota
+	// SecretField is a single line of text that is not shown.
+	SecretField
+	// ChoiceField is one of the field options.
+	ChoiceField
+	// MultiChoiceField is any number of the field options.
+	MultiChoiceField
+)
+
+// FormField is a field of a form. The value of the field is Value for text and
+// choice fields, and Values for multi-choice fields.
+type FormField struct {
+	Key         string
+	Label       string
+	Kind        FieldKind
+	Placeholder string
+	Value       string
+	Values      []string
+	Options     []string
+	// Descriptions are shown next to the options of multi-choice fields.
+	Descriptions map[string]string
+	// Validate checks the value of the field. Its error is shown next to the
+	// field, and the form cannot be submitted until it is fixed.
+	Validate func(FormField) error
+}
+
+// display is the value of the field as shown in the form and review screens.
+func (f FormField) display() string {
+	switch f.Kind {
+	case SecretField:
+		if f.Value == "" {
+			return ""
+		}
+		return strings.Repeat("•", 8)
+	case MultiChoiceField:
+		return strings.Join(f.Values, ", ")
+	}
+	return f.Value
+}
+
+type formKeyMap struct {
+	Next   key.Binding
+	Prev   key.Binding
+	Change key.Binding
+	Submit key.Binding
+	Quit   key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k formKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Next, k.Change, k.Submit, k.Quit}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k formKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Next, k.Prev, k.Change},
+		{k.Submit, k.Quit},
+	}
+}
+
+var formKeys = formKeyMap{
+	Next: key.NewBinding(
+		key.WithKeys("tab", "down"),
+		key.WithHelp("tab/↓", "next field"),
+	),
+	Prev: key.NewBinding(
+		key.WithKeys("shift+tab", "up"),
+		key.WithHelp("shift+tab/↑", "previous field"),
+	),
+	Change: key.NewBinding(
+		key.WithKeys("left", "right", " "),
+		key.WithHelp("←/→/space", "change choice"),
+	),
+	Submit: key.NewBinding(
+		key.WithKeys("ctrl+s"),
+		key.WithHelp("ctrl+s", "review"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("esc", "ctrl+c"),
+		key.WithHelp("esc", "quit"),
+	),
+}
+
+// Form shows all fields at once, with tab navigation between them, inline
+// validation and a review screen that must be confirmed before the values are
+// returned, keyed by the field keys.
+func Form(title string, fields []FormField) (map[string]FormField, error) {
+	m := formModel{
+		title:  title,
+		fields: fields,
+		inputs: make([]textinput.Model, len(fields)),
+		errors: make([]error, len(fields)),
+		keys:   formKeys,
+		help:   help.New(),
+	}
+	for i, field := range fields {
+		input := textinput.New()
+		input.Placeholder = field.Placeholder
+		input.CursorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("212"))
+		input.SetValue(field.Value)
+		if field.Kind == SecretField {
+			input.EchoMode = textinput.EchoPassword
+			input.EchoCharacter = '•'
+		}
+		m.inputs[i] = input
+	}
+	m.focusCmd()
+
+	tm, err := tea.NewProgram(m, tea.WithOutput(os.Stderr)).Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start tea program: %w", err)
+	}
+	m = tm.(formModel)
+	if m.cancelled {
+		return nil, fmt.Errorf("cancelled")
+	}
+	values := map[string]FormField{}
+	for _, field := range m.fields {
+		values[field.Key] = field
+	}
+	return values, nil
+}
+
+type formModel struct {
+	title     string
+	fields    []FormField
+	inputs    []textinput.Model
+	errors    []error
+	focus     int
+	chooser   *chooseModel
+	reviewing bool
+	quitting  bool
+	cancelled bool
+	keys      formKeyMap
+	help      help.Model
+	width     int
+}
+
+func (m formModel) Init() tea.Cmd { return textinput.Blink }
+
+// focusCmd focuses the input of the focused field, if it has one.
+func (m *formModel) focusCmd() tea.Cmd {
+	for i := range m.inputs {
+		m.inputs[i].Blur()
+	}
+	kind := m.fields[m.focus].Kind
+	if kind == TextField || kind == SecretField {
+		return m.inputs[m.focus].Focus()
+	}
+	return nil
+}
+
+func (m *formModel) validate(i int) {
+	m.errors[i] = nil
+	if m.fields[i].Validate != nil {
+		m.errors[i] = m.fields[i].Validate(m.fields[i])
+	}
+}
+
+func (m *formModel) valid() bool {
+	ok := true
+	for i := range m.fields {
+		m.validate(i)
+		if m.errors[i] != nil {
+			ok = false
+		}
+	}
+	return ok
+}
+
+func (m formModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		return m, nil
+	case tea.KeyMsg:
+		if m.chooser != nil {
+			return m.updateChooser(msg)
+		}
+		if m.reviewing {
+			switch msg.String() {
+			case "enter", "y":
+				m.quitting = true
+				return m, tea.Quit
+			case "ctrl+c":
+				m.cancelled = true
+				m.quitting = true
+				return m, tea.Quit
+			case "esc", "n":
+				m.reviewing = false
+			}
+			return m, nil
+		}
+
+		field := m.fields[m.focus]
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			m.cancelled = true
+			m.quitting = true
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Submit):
+			m.reviewing = m.valid()
+			return m, nil
+		case key.Matches(msg, m.keys.Next):
+			m.validate(m.focus)
+			m.focus = (m.focus + 1) % len(m.fields)
+			return m, m.focusCmd()
+		case key.Matches(msg, m.keys.Prev):
+			m.validate(m.focus)
+			m.focus = (m.focus - 1 + len(m.fields)) % len(m.fields)
+			return m, m.focusCmd()
+		case msg.String() == "enter":
+			switch {
+			case field.Kind == MultiChoiceField:
+				chooser := newChooseModel(field.Label, field.Options, 0, WithDescriptions(field.Descriptions), WithSelected(field.Values))
+				chooser.embedded = true
+				chooser.width = m.width
+				m.chooser = &chooser
+				return m, nil
+			case m.focus == len(m.fields)-1:
+				m.reviewing = m.valid()
+				return m, nil
+			}
+			m.validate(m.focus)
+			m.focus++
+			return m, m.focusCmd()
+		case field.Kind == ChoiceField && key.Matches(msg, m.keys.Change):
+			m.fields[m.focus].Value = cycle(field.Options, field.Value, msg.String() == "left")
+			m.validate(m.focus)
+			return m, nil
+		case field.Kind == TextField || field.Kind == SecretField:
+			var cmd tea.Cmd
+			m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+			m.fields[m.focus].Value = m.inputs[m.focus].Value()
+			if m.errors[m.focus] != nil {
+				m.validate(m.focus)
+			}
+			return m, cmd
+		}
+	}
+	return m, nil
+}
+
+// updateChooser passes keys to the chooser of a multi-choice field until it is
+// confirmed or cancelled.
+func (m formModel) updateChooser(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.chooser.Update(msg)
+	chooser := updated.(chooseModel)
+	if !chooser.quitting {
+		m.chooser = &chooser
+		return m, cmd
+	}
+	if !chooser.cancelled {
+		m.fields[m.focus].Values = chooser.selection()
+		m.validate(m.focus)
+	}
+	m.chooser = nil
+	return m, nil
+}
+
+// cycle returns the option after current, or before it when backwards is set.
+func cycle(options []string, current string, backwards bool) string {
+	if len(options) == 0 {
+		return current
+	}
+	index := 0
+	for i, option := range options {
+		if option == current {
+			index = i
+		}
+	}
+	if backwards {
+		return options[(index-1+len(options))%len(options)]
+	}
+	return options[(index+1)%len(options)]
+}
+
+func (m formModel) View() string {
+	if m.quitting {
+		return ""
+	}
+	if m.chooser != nil {
+		return m.chooser.View()
+	}
+
+	var s strings.Builder
+	labelWidth := 0
+	for _, field := range m.fields {
+		labelWidth = max(labelWidth, runewidth.StringWidth(field.Label))
+	}
+
+	if m.reviewing {
+		s.WriteString(highlightStyle.Render("Review "+strings.ToLower(m.title)) + "\n\n")
+		for _, field := range m.fields {
+			value := field.display()
+			if value == "" {
+				value = subduedStyle.Render("(none)")
+			}
+			s.WriteString(runewidth.FillRight(field.Label, labelWidth) + "  " + value + "\n")
+		}
+		s.WriteString("\n" + subduedStyle.Render("enter confirm • esc back to editing"))
+		return s.String()
+	}
+
+	s.WriteString(highlightStyle.Render(m.title) + "\n\n")
+	for i, field := range m.fields {
+		cursor := "  "
+		label := runewidth.FillRight(field.Label, labelWidth)
+		if i == m.focus {
+			cursor = highlightStyle.Render("> ")
+			label = highlightStyle.Render(label)
+		}
+		s.WriteString(cursor + label + "  ")
+		switch field.Kind {
+		case TextField, SecretField:
+			s.WriteString(m.inputs[i].View())
+		case ChoiceField:
+			s.WriteString("‹ " + field.Value + " ›")
+		case MultiChoiceField:
+			value := field.display()
+			if m.width > 0 {
+				value = runewidth.Truncate(value, max(m.width-labelWidth-30, 10), "…")
+			}
+			if value == "" {
+				value = subduedStyle.Render("none")
+			}
+			s.WriteString(value + subduedStyle.Render("  (enter to choose)"))
+		}
+		if m.errors[i] != nil {
+			s.WriteString("  " + errorStyle.Render(m.errors[i].Error()))
+		}
+		s.WriteRune('\n')
+	}
+	s.WriteString("\n" + m.help.View(m.keys))
+	return s.String()
+}
@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// fuzzyMatch reports whether all runes of pattern appear in text in order,
+// ignoring case. The score favours matches that are consecutive or start at a
+// word boundary, and positions are the indices of the matched runes in text.
+func fuzzyMatch(pattern, text string) (score int, positions []int, ok bool) {
+	p := []rune(strings.ToLower(pattern))
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+	t := []rune(strings.ToLower(text))
+	pi := 0
+	previous := -2
+	for ti, r := range t {
+		if pi == len(p) {
+			break
+		}
+		if r != p[pi] {
+			continue
+		}
+		score++
+		if ti == previous+1 {
+			score += 2
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) {
+			score += 3
+		}
+		positions = append(positions, ti)
+		previous = ti
+		pi++
+	}
+	if pi < len(p) {
+		return 0, nil, false
+	}
+	return score, positions, true
+}
+
+// fuzzyFilter returns the indices of the texts that match pattern, best match
+// first, along with the matched rune positions of each text. Equally good
+// matches are ordered from shortest to longest text.
+func fuzzyFilter(pattern string, texts []string) ([]int, map[int][]int) {
+	var indices []int
+	scores := map[int]int{}
+	matches := map[int][]int{}
+	for i, text := range texts {
+		score, positions, ok := fuzzyMatch(pattern, text)
+		if !ok {
+			continue
+		}
+		indices = append(indices, i)
+		scores[i] = score
+		matches[i] = positions
+	}
+	sort.SliceStable(indices, func(a, b int) bool {
+		if scores[indices[a]] != scores[indices[b]] {
+			return scores[indices[a]] > scores[indices[b]]
+		}
+		return len(texts[indices[a]]) < len(texts[indices[b]])
+	})
+	return indices, matches
+}
@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_fuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name          string
+		pattern       string
+		text          string
+		wantPositions []int
+		wantOk        bool
+	}{
+		{
+			name:   "empty pattern matches everything",
+			text:   "push",
+			wantOk: true,
+		},
+		{
+			name:          "subsequence",
+			pattern:       "prt",
+			text:          "pull_request_review_thread",
+			wantPositions: []int{0, 5, 11},
+			wantOk:        true,
+		},
+		{
+			name:          "ignores case",
+			pattern:       "PUSH",
+			text:          "push",
+			wantPositions: []int{0, 1, 2, 3},
+			wantOk:        true,
+		},
+		{
+			name:    "out of order",
+			pattern: "hsup",
+			text:    "push",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, positions, ok := fuzzyMatch(tt.pattern, tt.text)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.wantPositions, positions)
+		})
+	}
+}
+
+func Test_fuzzyFilter(t *testing.T) {
+	texts := []string{"deployment_status", "status", "push", "star"}
+	indices, matches := fuzzyFilter("stat", texts)
+	assert.Equal(t, []int{1, 0}, indices)
+	assert.Equal(t, []int{0, 1, 2, 3}, matches[1])
+}